@@ -0,0 +1,228 @@
+package opt
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Binary format version 1 presence tags. Any other leading byte is rejected so
+// that future versions of the format can be distinguished.
+const (
+	binaryEmpty   byte = 0x00
+	binaryPresent byte = 0x01
+)
+
+// binaryAppender mirrors encoding.BinaryAppender which was added in Go 1.24.
+type binaryAppender interface {
+	AppendBinary(b []byte) ([]byte, error)
+}
+
+// AppendBinary appends the binary encoding of the optional to `b`. The encoding
+// is a one-byte presence tag, 0x00 for empty and 0x01 for present, followed by
+// the encoding of the wrapped value.
+//
+// The value is encoded using its own AppendBinary or MarshalBinary if it has
+// either, on a value or pointer receiver. Otherwise, bools and numeric kinds
+// use a fixed-width big-endian encoding (`int` and `uint` are always 8 bytes)
+// and strings are written as-is.
+func (o Optional[T]) AppendBinary(b []byte) ([]byte, error) {
+	if o == nil {
+		return append(b, binaryEmpty), nil
+	}
+
+	return appendBinary(append(b, binaryPresent), reflect.ValueOf(&o[0]).Elem())
+}
+
+// MarshalBinary marshals the optional using the encoding described by
+// AppendBinary.
+func (o Optional[T]) MarshalBinary() (data []byte, err error) {
+	return o.AppendBinary(nil)
+}
+
+// UnmarshalBinary unmarshals data produced by MarshalBinary into this optional.
+func (o *Optional[T]) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("opt: binary data is missing the presence tag")
+	}
+
+	switch data[0] {
+	case binaryEmpty:
+		if len(data) > 1 {
+			return fmt.Errorf("opt: binary data has %d trailing bytes after empty tag", len(data)-1)
+		}
+
+		*o = NewEmpty[T]()
+		return nil
+
+	case binaryPresent:
+		var v T
+		err := parseBinary(reflect.ValueOf(&v).Elem(), data[1:])
+		if err != nil {
+			return err
+		}
+
+		*o = New(v)
+		return nil
+	}
+
+	return fmt.Errorf("opt: invalid binary presence tag %#x", data[0])
+}
+
+func appendBinary(b []byte, v reflect.Value) ([]byte, error) {
+	if v.IsValid() && v.CanInterface() {
+		i := v.Interface()
+		if v.CanAddr() {
+			i = v.Addr().Interface()
+		}
+
+		switch m := i.(type) {
+		case binaryAppender:
+			return m.AppendBinary(b)
+
+		case encoding.BinaryMarshaler:
+			data, err := m.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			return append(b, data...), nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+
+	case reflect.Int8:
+		return append(b, byte(v.Int())), nil
+	case reflect.Int16:
+		return appendUint16(b, uint16(v.Int())), nil
+	case reflect.Int32:
+		return appendUint32(b, uint32(v.Int())), nil
+	case reflect.Int, reflect.Int64:
+		return appendUint64(b, uint64(v.Int())), nil
+
+	case reflect.Uint8:
+		return append(b, byte(v.Uint())), nil
+	case reflect.Uint16:
+		return appendUint16(b, uint16(v.Uint())), nil
+	case reflect.Uint32:
+		return appendUint32(b, uint32(v.Uint())), nil
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return appendUint64(b, v.Uint()), nil
+
+	case reflect.Float32:
+		return appendUint32(b, math.Float32bits(float32(v.Float()))), nil
+	case reflect.Float64:
+		return appendUint64(b, math.Float64bits(v.Float())), nil
+
+	case reflect.Complex64:
+		c := v.Complex()
+		b = appendUint32(b, math.Float32bits(float32(real(c))))
+		return appendUint32(b, math.Float32bits(float32(imag(c)))), nil
+	case reflect.Complex128:
+		c := v.Complex()
+		b = appendUint64(b, math.Float64bits(real(c)))
+		return appendUint64(b, math.Float64bits(imag(c))), nil
+
+	case reflect.String:
+		return append(b, v.String()...), nil
+	}
+
+	return nil, fmt.Errorf("opt: cannot encode %v as binary", v.Type())
+}
+
+func parseBinary(v reflect.Value, data []byte) error {
+	if u, ok := v.Addr().Interface().(encoding.BinaryUnmarshaler); ok {
+		return u.UnmarshalBinary(data)
+	}
+
+	size := 0
+	switch v.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Uint8:
+		size = 1
+	case reflect.Int16, reflect.Uint16:
+		size = 2
+	case reflect.Int32, reflect.Uint32, reflect.Float32:
+		size = 4
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Uintptr, reflect.Float64, reflect.Complex64:
+		size = 8
+	case reflect.Complex128:
+		size = 16
+	case reflect.String:
+		v.SetString(string(data))
+		return nil
+	default:
+		return fmt.Errorf("opt: cannot decode binary into %v", v.Type())
+	}
+
+	if len(data) != size {
+		return fmt.Errorf("opt: binary %v must be %d bytes, got %d", v.Type(), size, len(data))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(data[0] != 0)
+
+	case reflect.Int8:
+		v.SetInt(int64(int8(data[0])))
+	case reflect.Int16:
+		v.SetInt(int64(int16(binary.BigEndian.Uint16(data))))
+	case reflect.Int32:
+		v.SetInt(int64(int32(binary.BigEndian.Uint32(data))))
+	case reflect.Int, reflect.Int64:
+		i := int64(binary.BigEndian.Uint64(data))
+		if v.OverflowInt(i) {
+			return fmt.Errorf("opt: binary value %d overflows %v", i, v.Type())
+		}
+		v.SetInt(i)
+
+	case reflect.Uint8:
+		v.SetUint(uint64(data[0]))
+	case reflect.Uint16:
+		v.SetUint(uint64(binary.BigEndian.Uint16(data)))
+	case reflect.Uint32:
+		v.SetUint(uint64(binary.BigEndian.Uint32(data)))
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		u := binary.BigEndian.Uint64(data)
+		if v.OverflowUint(u) {
+			return fmt.Errorf("opt: binary value %d overflows %v", u, v.Type())
+		}
+		v.SetUint(u)
+
+	case reflect.Float32:
+		v.SetFloat(float64(math.Float32frombits(binary.BigEndian.Uint32(data))))
+	case reflect.Float64:
+		v.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(data)))
+
+	case reflect.Complex64:
+		r := math.Float32frombits(binary.BigEndian.Uint32(data[:4]))
+		i := math.Float32frombits(binary.BigEndian.Uint32(data[4:]))
+		v.SetComplex(complex(float64(r), float64(i)))
+	case reflect.Complex128:
+		r := math.Float64frombits(binary.BigEndian.Uint64(data[:8]))
+		i := math.Float64frombits(binary.BigEndian.Uint64(data[8:]))
+		v.SetComplex(complex(r, i))
+	}
+
+	return nil
+}
+
+// The append helpers match binary.BigEndian.AppendUintN which need Go 1.19.
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v>>32)), uint32(v))
+}
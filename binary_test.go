@@ -0,0 +1,99 @@
+package opt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	a := assert.New(t)
+
+	tests := []struct {
+		Marshal  func() ([]byte, error)
+		Expected []byte
+	}{
+		{NewEmpty[int]().MarshalBinary, []byte{0x00}},
+		{New(true).MarshalBinary, []byte{0x01, 0x01}},
+		{New[int8](-1).MarshalBinary, []byte{0x01, 0xff}},
+		{New[uint16](0x0102).MarshalBinary, []byte{0x01, 0x01, 0x02}},
+		{New[int32](-2).MarshalBinary, []byte{0x01, 0xff, 0xff, 0xff, 0xfe}},
+		{New(0).MarshalBinary, []byte{0x01, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{New[uint64](1).MarshalBinary, []byte{0x01, 0, 0, 0, 0, 0, 0, 0, 1}},
+		{New[float32](1).MarshalBinary, []byte{0x01, 0x3f, 0x80, 0x00, 0x00}},
+		{New("hi").MarshalBinary, []byte{0x01, 'h', 'i'}},
+		{New("").MarshalBinary, []byte{0x01}},
+	}
+
+	for _, test := range tests {
+		b, err := test.Marshal()
+		a.NoError(err)
+		a.Equal(test.Expected, b)
+	}
+
+	_, err := New(struct{}{}).MarshalBinary()
+	a.Error(err)
+}
+
+func TestAppendBinary(t *testing.T) {
+	a := assert.New(t)
+
+	b, err := New[uint8](7).AppendBinary([]byte{0xaa})
+	a.NoError(err)
+	a.Equal([]byte{0xaa, 0x01, 0x07}, b)
+
+	b, err = NewEmpty[uint8]().AppendBinary(b)
+	a.NoError(err)
+	a.Equal([]byte{0xaa, 0x01, 0x07, 0x00}, b)
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	a := assert.New(t)
+
+	var i Optional[int]
+	b, err := New(-69).MarshalBinary()
+	a.NoError(err)
+	a.NoError(i.UnmarshalBinary(b))
+	a.Equal(New(-69), i)
+
+	b, err = NewEmpty[int]().MarshalBinary()
+	a.NoError(err)
+	a.NoError(i.UnmarshalBinary(b))
+	a.Empty(i)
+
+	var f Optional[float64]
+	b, err = New(2.5).MarshalBinary()
+	a.NoError(err)
+	a.NoError(f.UnmarshalBinary(b))
+	a.Equal(New(2.5), f)
+
+	var c Optional[complex128]
+	b, err = New(1 + 2i).MarshalBinary()
+	a.NoError(err)
+	a.NoError(c.UnmarshalBinary(b))
+	a.Equal(New(1+2i), c)
+
+	var s Optional[string]
+	b, err = New("").MarshalBinary()
+	a.NoError(err)
+	a.NoError(s.UnmarshalBinary(b))
+	a.Equal(New(""), s)
+
+	var tm Optional[time.Time]
+	b, err = New(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)).MarshalBinary()
+	a.NoError(err)
+	a.NoError(tm.UnmarshalBinary(b))
+	a.Equal(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), tm.OrZero())
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	a := assert.New(t)
+
+	var i Optional[int32]
+	a.Error(i.UnmarshalBinary(nil))
+	a.Error(i.UnmarshalBinary([]byte{0x02}))
+	a.Error(i.UnmarshalBinary([]byte{0x00, 0x00}))
+	a.Error(i.UnmarshalBinary([]byte{0x01, 0x00}))
+	a.Empty(i)
+}
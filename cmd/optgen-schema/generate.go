@@ -0,0 +1,471 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// generator turns schemas into Go type declarations.
+type generator struct {
+	pkg     string
+	types   []*goType
+	names   map[string]bool
+	imports map[string]bool
+
+	// refs maps the "$ref" of each definition to the name of its type.
+	refs map[string]string
+}
+
+type goType struct {
+	name string
+	doc  string
+
+	// underlying is set for types which aren't structs, such as `type ID string`.
+	underlying string
+	fields     []goField
+}
+
+type goField struct {
+	name string
+	typ  string
+	tag  string
+	doc  string
+}
+
+func newGenerator(pkg string) *generator {
+	return &generator{pkg: pkg, names: map[string]bool{}, imports: map[string]bool{}, refs: map[string]string{}}
+}
+
+// addDocument adds every definition in the document, which may be a JSON Schema
+// with "$defs" or "definitions" or an OpenAPI document with component schemas.
+// If the document itself describes an object it's added too, named after its
+// title or `root`.
+func (g *generator) addDocument(doc *schema, root string) error {
+	var defs properties
+	var refs []string
+	for _, section := range []struct {
+		prefix string
+		defs   properties
+	}{
+		{"#/components/schemas/", doc.Components.Schemas},
+		{"#/$defs/", doc.Defs},
+		{"#/definitions/", doc.Definitions},
+	} {
+		for _, d := range section.defs {
+			defs = append(defs, d)
+			refs = append(refs, section.prefix+d.name)
+		}
+	}
+
+	// Name definitions first so references to them resolve regardless of the
+	// order they're declared in. Names such as "user" and "User" would both
+	// become User, so later ones are numbered like nested types are.
+	for i, d := range defs {
+		name := goName(d.name)
+		if name == "" {
+			name = "Type"
+		}
+		g.refs[refs[i]] = g.unique(name)
+	}
+
+	if len(doc.Properties) > 0 {
+		name := goName(doc.Title)
+		if name == "" {
+			name = root
+		}
+		if _, err := g.define(g.unique(name), doc); err != nil {
+			return err
+		}
+	}
+
+	for i, d := range defs {
+		if _, err := g.define(g.refs[refs[i]], d.schema); err != nil {
+			return fmt.Errorf("%s: %w", d.name, err)
+		}
+	}
+
+	return nil
+}
+
+// unique returns `name`, or `name` with a number appended if it's taken.
+func (g *generator) unique(name string) string {
+	candidate := name
+	for i := 2; g.names[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	g.names[candidate] = true
+	return candidate
+}
+
+// define declares a named type for `s`.
+func (g *generator) define(name string, s *schema) (string, error) {
+	t := &goType{name: name, doc: s.Description}
+	g.types = append(g.types, t)
+
+	if len(s.Properties) == 0 {
+		// Whether the value may be null is decided where the type is used.
+		typ, _, err := g.typeOf(s, name+"Value")
+		if err != nil {
+			return "", err
+		}
+		t.underlying = typ
+		return name, nil
+	}
+
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+
+	fieldNames := map[string]bool{}
+	for _, p := range s.Properties {
+		// Names such as "_" or "$" have nothing to build an identifier from.
+		base := goName(p.name)
+		if base == "" {
+			base = "Field"
+		}
+		fieldName := base
+		for i := 2; fieldNames[fieldName]; i++ {
+			fieldName = fmt.Sprintf("%s%d", base, i)
+		}
+		fieldNames[fieldName] = true
+
+		typ, nullable, err := g.typeOf(p.schema, name+fieldName)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", p.name, err)
+		}
+
+		tag := p.name
+		if !required[p.name] {
+			tag += ",omitempty"
+		}
+		if !required[p.name] || nullable {
+			typ = g.optional(typ)
+		}
+
+		t.fields = append(t.fields, goField{
+			name: fieldName,
+			typ:  typ,
+			tag:  fmt.Sprintf("`json:%q`", tag),
+			doc:  p.schema.Description,
+		})
+	}
+
+	return name, nil
+}
+
+func (g *generator) optional(typ string) string {
+	g.imports["github.com/Southclaws/opt"] = true
+	return "opt.Optional[" + typ + "]"
+}
+
+// typeOf returns the Go type for `s` and whether it permits null. Objects with
+// properties are declared as new types named `hint`.
+func (g *generator) typeOf(s *schema, hint string) (typ string, nullable bool, err error) {
+	if s.Ref != "" {
+		name, ok := g.refs[s.Ref]
+		if !ok {
+			return "", false, fmt.Errorf("unresolved $ref %q", s.Ref)
+		}
+		return name, s.Nullable, nil
+	}
+
+	if alternatives := append(append([]*schema{}, s.AnyOf...), s.OneOf...); len(alternatives) > 0 {
+		var nonNull []*schema
+		for _, a := range alternatives {
+			if a.isNull() {
+				nullable = true
+			} else {
+				nonNull = append(nonNull, a)
+			}
+		}
+		if len(nonNull) != 1 {
+			return "any", true, nil
+		}
+		typ, n, err := g.typeOf(nonNull[0], hint)
+		return typ, nullable || n || s.Nullable, err
+	}
+
+	var types []string
+	for _, t := range s.Type {
+		if t == "null" {
+			nullable = true
+		} else {
+			types = append(types, t)
+		}
+	}
+	nullable = nullable || s.Nullable
+
+	var kind string
+	switch {
+	case len(types) == 1:
+		kind = types[0]
+	case len(types) > 1:
+		return "any", true, nil
+	case len(s.Properties) > 0:
+		kind = "object"
+	case s.Items != nil:
+		kind = "array"
+	default:
+		return "any", true, nil
+	}
+
+	switch kind {
+	case "string":
+		switch {
+		case s.Format == "date-time":
+			g.imports["time"] = true
+			return "time.Time", nullable, nil
+		case s.Format == "byte" || s.ContentEncoding == "base64":
+			return "[]byte", nullable, nil
+		}
+		return "string", nullable, nil
+
+	case "integer":
+		if s.Format == "int32" {
+			return "int32", nullable, nil
+		}
+		return "int64", nullable, nil
+
+	case "number":
+		if s.Format == "float" {
+			return "float32", nullable, nil
+		}
+		return "float64", nullable, nil
+
+	case "boolean":
+		return "bool", nullable, nil
+
+	case "array":
+		if s.Items == nil {
+			return "[]any", nullable, nil
+		}
+		elem, elemNullable, err := g.typeOf(s.Items, hint+"Item")
+		if err != nil {
+			return "", false, err
+		}
+		if elemNullable && elem != "any" {
+			elem = g.optional(elem)
+		}
+		return "[]" + elem, nullable, nil
+
+	case "object":
+		if len(s.Properties) > 0 {
+			name, err := g.define(g.unique(hint), s)
+			return name, nullable, err
+		}
+		additional, err := s.additional()
+		if err != nil {
+			return "", false, err
+		}
+		if additional == nil {
+			return "map[string]any", nullable, nil
+		}
+		value, valueNullable, err := g.typeOf(additional, hint+"Value")
+		if err != nil {
+			return "", false, err
+		}
+		if valueNullable && value != "any" {
+			value = g.optional(value)
+		}
+		return "map[string]" + value, nullable, nil
+	}
+
+	return "", false, fmt.Errorf("unsupported type %q", kind)
+}
+
+// source renders the declared types as a formatted Go file.
+func (g *generator) source() ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by optgen-schema. DO NOT EDIT.\n\npackage %s\n\n", g.pkg)
+	writeImports(&b, g.imports)
+
+	for _, t := range g.types {
+		writeDoc(&b, "", t.doc)
+		if t.fields == nil && t.underlying != "" {
+			fmt.Fprintf(&b, "type %s %s\n\n", t.name, t.underlying)
+			continue
+		}
+		fmt.Fprintf(&b, "type %s struct {\n", t.name)
+		for i, f := range t.fields {
+			if f.doc != "" && i > 0 {
+				b.WriteString("\n")
+			}
+			writeDoc(&b, "\t", f.doc)
+			fmt.Fprintf(&b, "\t%s %s %s\n", f.name, f.typ, f.tag)
+		}
+		b.WriteString("}\n\n")
+	}
+
+	return format.Source(b.Bytes())
+}
+
+// testSource renders a test file which checks that each struct survives a
+// round-trip through encoding/json, both empty and with every field set.
+func (g *generator) testSource() ([]byte, error) {
+	imports := map[string]bool{"encoding/json": true, "reflect": true, "testing": true}
+	types := map[string]*goType{}
+	for _, t := range g.types {
+		types[t.name] = t
+	}
+
+	var body bytes.Buffer
+	for _, t := range g.types {
+		if t.fields == nil {
+			continue
+		}
+		populated := strings.TrimPrefix(example(t.name, types, map[string]bool{}, imports), t.name)
+		fmt.Fprintf(&body, `func Test%[1]sJSON(t *testing.T) {
+	for _, in := range []%[1]s{
+		{},
+		%[2]s,
+	} {
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var out %[1]s
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(in, out) {
+			t.Errorf("%[1]s changed after a round-trip through %%s", data)
+		}
+	}
+}
+
+`, t.name, populated)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by optgen-schema. DO NOT EDIT.\n\npackage %s\n\n", g.pkg)
+	writeImports(&b, imports)
+	b.Write(body.Bytes())
+
+	return format.Source(b.Bytes())
+}
+
+// example returns an expression for a value of the Go type `typ` with every
+// optional present and every struct field set, so the generated tests cover
+// more than empty values. Structs which are already being built are left
+// empty so that recursive types terminate.
+func example(typ string, types map[string]*goType, building, imports map[string]bool) string {
+	switch {
+	case strings.HasPrefix(typ, "opt.Optional[") && strings.HasSuffix(typ, "]"):
+		imports["github.com/Southclaws/opt"] = true
+		elem := typ[len("opt.Optional[") : len(typ)-1]
+		return fmt.Sprintf("opt.New[%s](%s)", elem, example(elem, types, building, imports))
+	case typ == "[]byte":
+		return `[]byte("value")`
+	case strings.HasPrefix(typ, "[]"):
+		return fmt.Sprintf("%s{%s}", typ, example(typ[len("[]"):], types, building, imports))
+	case strings.HasPrefix(typ, "map[string]"):
+		return fmt.Sprintf(`%s{"key": %s}`, typ, example(typ[len("map[string]"):], types, building, imports))
+	case typ == "time.Time":
+		imports["time"] = true
+		return "time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)"
+	case typ == "string" || typ == "any":
+		return `"value"`
+	case typ == "int32" || typ == "int64":
+		return "1"
+	case typ == "float32" || typ == "float64":
+		return "1.5"
+	case typ == "bool":
+		return "true"
+	}
+
+	t, ok := types[typ]
+	if !ok || building[typ] {
+		return typ + "{}"
+	}
+	if t.fields == nil {
+		return fmt.Sprintf("%s(%s)", typ, example(t.underlying, types, building, imports))
+	}
+
+	building[typ] = true
+	defer delete(building, typ)
+
+	var b strings.Builder
+	b.WriteString(typ + "{\n")
+	for _, f := range t.fields {
+		fmt.Fprintf(&b, "%s: %s,\n", f.name, example(f.typ, types, building, imports))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func writeImports(b *bytes.Buffer, imports map[string]bool) {
+	if len(imports) == 0 {
+		return
+	}
+
+	var std, other []string
+	for path := range imports {
+		if strings.Contains(path, ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	b.WriteString("import (\n")
+	for _, path := range std {
+		fmt.Fprintf(b, "\t%q\n", path)
+	}
+	if len(std) > 0 && len(other) > 0 {
+		b.WriteString("\n")
+	}
+	for _, path := range other {
+		fmt.Fprintf(b, "\t%q\n", path)
+	}
+	b.WriteString(")\n\n")
+}
+
+func writeDoc(b *bytes.Buffer, indent, doc string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(b, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
+
+// initialisms are written in upper case in Go names, following Go's style.
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "SQL": true, "URI": true, "URL": true,
+	"UUID": true, "XML": true,
+}
+
+// goName converts a schema or property name such as "created_at" or
+// "user-id" into an exported Go identifier such as "CreatedAt" or "UserID".
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var sb strings.Builder
+	for _, w := range words {
+		if initialisms[strings.ToUpper(w)] {
+			sb.WriteString(strings.ToUpper(w))
+			continue
+		}
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+
+	s := sb.String()
+	if s != "" && unicode.IsDigit([]rune(s)[0]) {
+		s = "X" + s
+	}
+	return s
+}
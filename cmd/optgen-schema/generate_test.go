@@ -0,0 +1,245 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExample checks that the checked-in example package is up to date, as it's
+// what proves the generated code compiles and its tests pass.
+func TestExample(t *testing.T) {
+	a := assert.New(t)
+	dir := filepath.Join("internal", "example")
+
+	schema, err := os.ReadFile(filepath.Join(dir, "schema.json"))
+	a.NoError(err)
+	models, err := os.ReadFile(filepath.Join(dir, "models.go"))
+	a.NoError(err)
+	modelsTest, err := os.ReadFile(filepath.Join(dir, "models_test.go"))
+	a.NoError(err)
+
+	src, testSrc, err := generate(schema, "example", "Root")
+	a.NoError(err)
+	a.Equal(string(models), string(src), "run go generate in %s", dir)
+	a.Equal(string(modelsTest), string(testSrc), "run go generate in %s", dir)
+}
+
+func TestGenerateOpenAPI(t *testing.T) {
+	a := assert.New(t)
+
+	src, testSrc, err := generate([]byte(`{
+		"openapi": "3.0.3",
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"required": ["id", "owner"],
+					"properties": {
+						"id": {"type": "integer", "format": "int32"},
+						"owner": {"$ref": "#/components/schemas/Owner", "nullable": true},
+						"weight": {"type": "number", "format": "float"},
+						"born": {"type": "string", "format": "date-time", "nullable": true},
+						"photo": {"type": "string", "format": "byte"},
+						"kind": {"oneOf": [{"type": "string"}, {"type": "integer"}]},
+						"labels": {"type": "array", "items": {"type": "string", "nullable": true}},
+						"visits": {"type": "array", "items": {"type": "object", "properties": {"at": {"type": "string"}}}},
+						"extra": {"type": "object", "additionalProperties": false},
+						"user-id": {"type": "string"},
+						"user_id": {"type": "string"},
+						"3d": {"type": "boolean"}
+					}
+				},
+				"Owner": {
+					"type": "object",
+					"properties": {"name": {"type": "string"}}
+				},
+				"PetVisitsItem": {"type": "string"}
+			}
+		}
+	}`), "models", "Root")
+	a.NoError(err)
+
+	lines := collapse(string(src))
+	for _, want := range []string{
+		`import (`,
+		`"time"`,
+		`"github.com/Southclaws/opt"`,
+		`type Pet struct {`,
+		"ID int32 `json:\"id\"`",
+		"Owner opt.Optional[Owner] `json:\"owner\"`",
+		"Weight opt.Optional[float32] `json:\"weight,omitempty\"`",
+		"Born opt.Optional[time.Time] `json:\"born,omitempty\"`",
+		"Photo opt.Optional[[]byte] `json:\"photo,omitempty\"`",
+		"Kind opt.Optional[any] `json:\"kind,omitempty\"`",
+		"Labels opt.Optional[[]opt.Optional[string]] `json:\"labels,omitempty\"`",
+		"Visits opt.Optional[[]PetVisitsItem2] `json:\"visits,omitempty\"`",
+		"Extra opt.Optional[map[string]any] `json:\"extra,omitempty\"`",
+		"UserID opt.Optional[string] `json:\"user-id,omitempty\"`",
+		"UserID2 opt.Optional[string] `json:\"user_id,omitempty\"`",
+		"X3d opt.Optional[bool] `json:\"3d,omitempty\"`",
+		`type Owner struct {`,
+		"Name opt.Optional[string] `json:\"name,omitempty\"`",
+		`type PetVisitsItem string`,
+		`type PetVisitsItem2 struct {`,
+		"At opt.Optional[string] `json:\"at,omitempty\"`",
+	} {
+		a.Contains(lines, want)
+	}
+
+	a.Contains(string(testSrc), "func TestPetJSON(t *testing.T) {")
+	a.Contains(string(testSrc), "func TestOwnerJSON(t *testing.T) {")
+	a.Contains(string(testSrc), "func TestPetVisitsItem2JSON(t *testing.T) {")
+	a.NotContains(string(testSrc), "func TestPetVisitsItemJSON(")
+}
+
+// collapse splits `src` into lines with their whitespace collapsed, so they can
+// be compared regardless of how gofmt aligned them.
+func collapse(src string) []string {
+	var lines []string
+	for _, line := range strings.Split(src, "\n") {
+		if f := strings.Fields(line); len(f) > 0 {
+			lines = append(lines, strings.Join(f, " "))
+		}
+	}
+	return lines
+}
+
+func TestGenerateTestFixtures(t *testing.T) {
+	a := assert.New(t)
+
+	_, testSrc, err := generate([]byte(`{
+		"$defs": {
+			"node": {
+				"type": "object",
+				"required": ["name", "label"],
+				"properties": {
+					"name": {"type": "string"},
+					"label": {"type": ["string", "null"]},
+					"parent": {"$ref": "#/$defs/node"},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}
+				}
+			}
+		}
+	}`), "models", "Root")
+	a.NoError(err)
+
+	lines := collapse(string(testSrc))
+	for _, want := range []string{
+		`for _, in := range []Node{`,
+		`{},`,
+		`Name: "value",`,
+		`Label: opt.New[string]("value"),`,
+		`Parent: opt.New[Node](Node{}),`,
+		`Children: opt.New[[]Node]([]Node{Node{}}),`,
+	} {
+		a.Contains(lines, want)
+	}
+}
+
+func TestGenerateNameCollisions(t *testing.T) {
+	a := assert.New(t)
+
+	src, _, err := generate([]byte(`{
+		"$defs": {
+			"user": {"type": "object", "properties": {"a": {"$ref": "#/$defs/User"}}},
+			"User": {"type": "object", "properties": {"b": {"$ref": "#/$defs/user"}}},
+			"user_b": {"type": "string"}
+		},
+		"definitions": {
+			"user": {"type": "integer"}
+		}
+	}`), "models", "Root")
+	a.NoError(err)
+
+	lines := collapse(string(src))
+	for _, want := range []string{
+		`type User struct {`,
+		"A opt.Optional[User2] `json:\"a,omitempty\"`",
+		`type User2 struct {`,
+		"B opt.Optional[User] `json:\"b,omitempty\"`",
+		`type UserB string`,
+		`type User3 int64`,
+	} {
+		a.Contains(lines, want)
+	}
+}
+
+func TestGenerateSymbolNames(t *testing.T) {
+	a := assert.New(t)
+
+	src, _, err := generate([]byte(`{
+		"$defs": {
+			"$": {
+				"type": "object",
+				"properties": {
+					"_": {"type": "string"},
+					"$": {"type": "integer"},
+					"-": {"$ref": "#/$defs/$"}
+				}
+			}
+		}
+	}`), "models", "Root")
+	a.NoError(err)
+
+	lines := collapse(string(src))
+	for _, want := range []string{
+		`type Type struct {`,
+		"Field opt.Optional[string] `json:\"_,omitempty\"`",
+		"Field2 opt.Optional[int64] `json:\"$,omitempty\"`",
+		"Field3 opt.Optional[Type] `json:\"-,omitempty\"`",
+	} {
+		a.Contains(lines, want)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	a := assert.New(t)
+
+	_, _, err := generate([]byte(`{`), "models", "Root")
+	a.Error(err)
+
+	_, _, err = generate([]byte(`{"type": "string"}`), "models", "Root")
+	a.EqualError(err, "no definitions or properties found in schema")
+
+	_, _, err = generate([]byte(`{"properties": {"a": {"type": "tuple"}}}`), "models", "Root")
+	a.EqualError(err, `a: unsupported type "tuple"`)
+
+	_, _, err = generate([]byte(`{"properties": {"a": {"$ref": "#/$defs/missing"}}}`), "models", "Root")
+	a.EqualError(err, `a: unresolved $ref "#/$defs/missing"`)
+}
+
+func TestGoName(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal("CreatedAt", goName("created_at"))
+	a.Equal("UserID", goName("user-id"))
+	a.Equal("APIURL", goName("api.url"))
+	a.Equal("DisplayName", goName("displayName"))
+	a.Equal("X2fa", goName("2fa"))
+	a.Equal("", goName(""))
+}
+
+func TestRun(t *testing.T) {
+	a := assert.New(t)
+	dir := t.TempDir()
+
+	in := filepath.Join(dir, "schema.json")
+	a.NoError(os.WriteFile(in, []byte(`{"title": "thing", "properties": {"name": {"type": "string"}}}`), 0o644))
+
+	out, test := filepath.Join(dir, "models.go"), filepath.Join(dir, "models_test.go")
+	a.NoError(run(in, out, test, "things", "Root"))
+
+	src, err := os.ReadFile(out)
+	a.NoError(err)
+	a.Contains(collapse(string(src)), "Name opt.Optional[string] `json:\"name,omitempty\"`")
+
+	testSrc, err := os.ReadFile(test)
+	a.NoError(err)
+	a.Contains(string(testSrc), "func TestThingJSON(t *testing.T) {")
+
+	a.Error(run(filepath.Join(dir, "missing.json"), out, "", "things", "Root"))
+}
@@ -0,0 +1,6 @@
+// Package example is generated by optgen-schema from schema.json. It's checked
+// in so that the generated code and its tests are compiled and run along with
+// the rest of the module.
+package example
+
+//go:generate go run ../.. -in schema.json -package example -out models.go -test models_test.go
@@ -0,0 +1,46 @@
+// Code generated by optgen-schema. DO NOT EDIT.
+
+package example
+
+import (
+	"time"
+
+	"github.com/Southclaws/opt"
+)
+
+// Account is a user's account.
+type Account struct {
+	ID int64 `json:"id"`
+
+	// Email is where notifications are sent.
+	Email       string                         `json:"email"`
+	DisplayName opt.Optional[string]           `json:"display_name"`
+	CreatedAt   time.Time                      `json:"created_at"`
+	Status      Status                         `json:"status"`
+	Address     opt.Optional[Address]          `json:"address,omitempty"`
+	Tags        opt.Optional[[]string]         `json:"tags,omitempty"`
+	Settings    opt.Optional[AccountSettings]  `json:"settings,omitempty"`
+	Limits      opt.Optional[map[string]int32] `json:"limits,omitempty"`
+	Avatar      opt.Optional[[]byte]           `json:"avatar,omitempty"`
+	Manager     opt.Optional[AccountRef]       `json:"manager,omitempty"`
+	Metadata    opt.Optional[any]              `json:"metadata,omitempty"`
+}
+
+type AccountSettings struct {
+	Theme         string             `json:"theme"`
+	Notifications opt.Optional[bool] `json:"notifications,omitempty"`
+}
+
+// Status is the state of an account.
+type Status string
+
+type Address struct {
+	Street   string               `json:"street"`
+	City     string               `json:"city"`
+	Postcode opt.Optional[string] `json:"postcode,omitempty"`
+}
+
+type AccountRef struct {
+	ID  int64                `json:"id"`
+	URL opt.Optional[string] `json:"url,omitempty"`
+}
@@ -0,0 +1,125 @@
+// Code generated by optgen-schema. DO NOT EDIT.
+
+package example
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Southclaws/opt"
+)
+
+func TestAccountJSON(t *testing.T) {
+	for _, in := range []Account{
+		{},
+		{
+			ID:          1,
+			Email:       "value",
+			DisplayName: opt.New[string]("value"),
+			CreatedAt:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Status:      Status("value"),
+			Address: opt.New[Address](Address{
+				Street:   "value",
+				City:     "value",
+				Postcode: opt.New[string]("value"),
+			}),
+			Tags: opt.New[[]string]([]string{"value"}),
+			Settings: opt.New[AccountSettings](AccountSettings{
+				Theme:         "value",
+				Notifications: opt.New[bool](true),
+			}),
+			Limits: opt.New[map[string]int32](map[string]int32{"key": 1}),
+			Avatar: opt.New[[]byte]([]byte("value")),
+			Manager: opt.New[AccountRef](AccountRef{
+				ID:  1,
+				URL: opt.New[string]("value"),
+			}),
+			Metadata: opt.New[any]("value"),
+		},
+	} {
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var out Account
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(in, out) {
+			t.Errorf("Account changed after a round-trip through %s", data)
+		}
+	}
+}
+
+func TestAccountSettingsJSON(t *testing.T) {
+	for _, in := range []AccountSettings{
+		{},
+		{
+			Theme:         "value",
+			Notifications: opt.New[bool](true),
+		},
+	} {
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var out AccountSettings
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(in, out) {
+			t.Errorf("AccountSettings changed after a round-trip through %s", data)
+		}
+	}
+}
+
+func TestAddressJSON(t *testing.T) {
+	for _, in := range []Address{
+		{},
+		{
+			Street:   "value",
+			City:     "value",
+			Postcode: opt.New[string]("value"),
+		},
+	} {
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var out Address
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(in, out) {
+			t.Errorf("Address changed after a round-trip through %s", data)
+		}
+	}
+}
+
+func TestAccountRefJSON(t *testing.T) {
+	for _, in := range []AccountRef{
+		{},
+		{
+			ID:  1,
+			URL: opt.New[string]("value"),
+		},
+	} {
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var out AccountRef
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(in, out) {
+			t.Errorf("AccountRef changed after a round-trip through %s", data)
+		}
+	}
+}
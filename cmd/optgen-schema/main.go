@@ -0,0 +1,105 @@
+// Command optgen-schema generates Go structs from JSON Schema or OpenAPI
+// component definitions, using opt.Optional for every property which isn't
+// required or which may be null.
+//
+// Usage:
+//
+//	optgen-schema -in schema.json -package models -out models.go -test models_test.go
+//
+// The input may be a JSON Schema document, whose "$defs" or "definitions" are
+// generated along with the document itself if it describes an object, or an
+// OpenAPI 3 document, whose "components/schemas" are generated. YAML documents
+// must be converted to JSON first.
+//
+// Properties which aren't required are tagged `omitempty` so an empty optional
+// is left out when encoding, as it was absent when decoding. Required
+// properties which may be null are optional but always encoded, as `null` when
+// empty.
+//
+// If -test is given, a test file is also written which checks that every
+// generated struct survives a round-trip through encoding/json, both empty and
+// with every field set.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	var (
+		in   = flag.String("in", "", "schema file to read, defaults to stdin")
+		out  = flag.String("out", "", "Go file to write, defaults to stdout")
+		test = flag.String("test", "", "Go test file to write, if any")
+		pkg  = flag.String("package", "models", "package name of the generated code")
+		root = flag.String("root", "Root", "type name for the document itself if it has no title")
+	)
+	flag.Parse()
+
+	if err := run(*in, *out, *test, *pkg, *root); err != nil {
+		fmt.Fprintln(os.Stderr, "optgen-schema:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, test, pkg, root string) error {
+	var (
+		data []byte
+		err  error
+	)
+	if in == "" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(in)
+	}
+	if err != nil {
+		return err
+	}
+
+	src, testSrc, err := generate(data, pkg, root)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(out, src, 0o644)
+	}
+	if err != nil {
+		return err
+	}
+
+	if test != "" {
+		return os.WriteFile(test, testSrc, 0o644)
+	}
+	return nil
+}
+
+// generate produces the source of the Go file and its test file from the
+// schema document `data`.
+func generate(data []byte, pkg, root string) (src, testSrc []byte, err error) {
+	var doc schema
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	g := newGenerator(pkg)
+	if err := g.addDocument(&doc, root); err != nil {
+		return nil, nil, err
+	}
+	if len(g.types) == 0 {
+		return nil, nil, fmt.Errorf("no definitions or properties found in schema")
+	}
+
+	if src, err = g.source(); err != nil {
+		return nil, nil, err
+	}
+	if testSrc, err = g.testSource(); err != nil {
+		return nil, nil, err
+	}
+	return src, testSrc, nil
+}
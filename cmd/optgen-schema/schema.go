@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Southclaws/opt/optschema"
+)
+
+// schema holds the JSON Schema and OpenAPI keywords which affect the generated
+// Go types. Everything else, such as validation keywords, is ignored.
+type schema struct {
+	Ref                  string          `json:"$ref"`
+	Title                string          `json:"title"`
+	Description          string          `json:"description"`
+	Type                 optschema.Types `json:"type"`
+	Format               string          `json:"format"`
+	ContentEncoding      string          `json:"contentEncoding"`
+	Nullable             bool            `json:"nullable"`
+	Items                *schema         `json:"items"`
+	Properties           properties      `json:"properties"`
+	Required             []string        `json:"required"`
+	AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	AnyOf                []*schema       `json:"anyOf"`
+	OneOf                []*schema       `json:"oneOf"`
+
+	// Definitions may be in any of these depending on the dialect.
+	Defs        properties `json:"$defs"`
+	Definitions properties `json:"definitions"`
+	Components  struct {
+		Schemas properties `json:"schemas"`
+	} `json:"components"`
+}
+
+// additional returns the schema of additionalProperties if it's a schema rather
+// than a boolean.
+func (s *schema) additional() (*schema, error) {
+	if len(s.AdditionalProperties) == 0 || s.AdditionalProperties[0] != '{' {
+		return nil, nil
+	}
+	var a schema
+	if err := json.Unmarshal(s.AdditionalProperties, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// isNull reports whether the schema only permits null.
+func (s *schema) isNull() bool {
+	return len(s.Type) == 1 && s.Type[0] == "null"
+}
+
+type property struct {
+	name   string
+	schema *schema
+}
+
+// properties is a JSON object of schemas which keeps the order of its keys, so
+// the generated code follows the order of the document.
+type properties []property
+
+func (p *properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t != json.Delim('{') {
+		return fmt.Errorf("expected an object of schemas, got %v", t)
+	}
+
+	*p = nil
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var s schema
+		if err := dec.Decode(&s); err != nil {
+			return err
+		}
+		*p = append(*p, property{name: t.(string), schema: &s})
+	}
+
+	_, err = dec.Token()
+	return err
+}
@@ -0,0 +1,86 @@
+package opt
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+// ApplyDefaults fills every empty optional field of the struct pointed to by
+// `dst` which has a `default:"..."` tag with the value of that tag, parsed in
+// the same way as UnmarshalText. This keeps default values next to the fields
+// they belong to instead of repeating them in calls to Or.
+//
+//	type Config struct {
+//		Host Optional[string] `default:"localhost"`
+//		Port Optional[int]    `default:"8080"`
+//	}
+//
+// Nested structs, non-nil pointers to structs and present optional structs are
+// walked too, as are the promoted fields of embedded structs, whether or not
+// the embedded type is exported. Every default is parsed, even those of fields
+// which are already present, so an invalid tag is reported as soon as this is
+// called rather than only when the field happens to be empty. A `default` tag
+// on a field which isn't optional is also an error.
+func ApplyDefaults(dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("opt: ApplyDefaults requires a non-nil pointer, got %T", dst)
+	}
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("opt: ApplyDefaults requires a pointer to a struct, got %T", dst)
+	}
+	return applyDefaults(v, "")
+}
+
+func applyDefaults(v reflect.Value, path string) error {
+	// There's no key for field names, so every field keeps its Go name and
+	// promoted fields are found the same way as the Go compiler finds them,
+	// including those of unexported embedded structs.
+	for _, f := range optreflect.Fields(v.Type(), "") {
+		fv := v.FieldByIndex(f.Index)
+		fieldPath := f.Name
+		if path != "" {
+			fieldPath = path + "." + f.Name
+		}
+
+		tag, hasDefault := f.Tag.Lookup("default")
+		if hasDefault {
+			if !optreflect.IsOptional(f.Type) {
+				return fmt.Errorf("opt: default tag on field %s which is not optional", fieldPath)
+			}
+			value := reflect.New(f.Type).Elem()
+			if err := value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(tag)); err != nil {
+				return fmt.Errorf("opt: invalid default %q for field %s of type %s: %w", tag, fieldPath, f.Type.Elem(), err)
+			}
+			if fv.Len() == 0 {
+				fv.Set(value)
+			}
+			continue
+		}
+
+		switch {
+		case isLayerable(f.Type):
+			if err := applyDefaults(fv, fieldPath); err != nil {
+				return err
+			}
+
+		case f.Type.Kind() == reflect.Pointer && isLayerable(f.Type.Elem()) && !fv.IsNil():
+			if err := applyDefaults(fv.Elem(), fieldPath); err != nil {
+				return err
+			}
+
+		case optreflect.IsOptional(f.Type) && isLayerable(f.Type.Elem()) && fv.Len() > 0:
+			inner := reflect.New(f.Type.Elem()).Elem()
+			inner.Set(fv.Index(0))
+			if err := applyDefaults(inner, fieldPath); err != nil {
+				return err
+			}
+			optreflect.Set(fv, inner)
+		}
+	}
+	return nil
+}
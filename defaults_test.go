@@ -0,0 +1,87 @@
+package opt
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type defaultsTLS struct {
+	Enabled Optional[bool]   `default:"true"`
+	Cert    Optional[string] `default:"cert.pem"`
+}
+
+type defaultsLogging struct {
+	Level Optional[string] `default:"info"`
+}
+
+type DefaultsMetrics struct {
+	Interval Optional[int] `default:"10"`
+}
+
+type defaultsConfig struct {
+	defaultsLogging
+	DefaultsMetrics
+	Host     Optional[string]     `default:"localhost"`
+	Port     Optional[int]        `default:"8080"`
+	Ratio    Optional[float64]    `default:"0.5"`
+	Addr     Optional[netip.Addr] `default:"127.0.0.1"`
+	Name     Optional[string]     `default:""`
+	NoTag    Optional[int]
+	TLS      defaultsTLS
+	Proxy    *defaultsTLS
+	Replica  Optional[defaultsTLS]
+	Fallback Optional[defaultsTLS]
+	internal Optional[int] `default:"1"`
+}
+
+func TestApplyDefaults(t *testing.T) {
+	a := assert.New(t)
+
+	cfg := defaultsConfig{
+		Port:    New(0),
+		Proxy:   &defaultsTLS{Cert: New("proxy.pem")},
+		Replica: New(defaultsTLS{Enabled: New(false)}),
+	}
+	a.NoError(ApplyDefaults(&cfg))
+
+	a.Equal(New("info"), cfg.Level, "fields promoted through unexported embedded structs")
+	a.Equal(New(10), cfg.Interval)
+	a.Equal(New("localhost"), cfg.Host)
+	a.Equal(New(0), cfg.Port, "present values are kept")
+	a.Equal(New(0.5), cfg.Ratio)
+	a.Equal(New(netip.MustParseAddr("127.0.0.1")), cfg.Addr)
+	a.Empty(cfg.Name, "empty text means empty")
+	a.Empty(cfg.NoTag)
+	a.Equal(defaultsTLS{Enabled: New(true), Cert: New("cert.pem")}, cfg.TLS)
+	a.Equal(&defaultsTLS{Enabled: New(true), Cert: New("proxy.pem")}, cfg.Proxy)
+	a.Equal(New(defaultsTLS{Enabled: New(false), Cert: New("cert.pem")}), cfg.Replica)
+	a.Empty(cfg.Fallback)
+	a.Empty(cfg.internal)
+}
+
+func TestApplyDefaultsErrors(t *testing.T) {
+	a := assert.New(t)
+
+	var invalid struct {
+		Nested struct {
+			Port Optional[int] `default:"http"`
+		}
+	}
+	invalid.Nested.Port = New(80)
+	err := ApplyDefaults(&invalid)
+	a.Error(err, "defaults are checked even when the field is present")
+	a.Contains(err.Error(), `invalid default "http" for field Nested.Port of type int`)
+
+	var notOptional struct {
+		Port int `default:"8080"`
+	}
+	err = ApplyDefaults(&notOptional)
+	a.Error(err)
+	a.Contains(err.Error(), "field Port which is not optional")
+
+	a.Error(ApplyDefaults(defaultsConfig{}))
+	a.Error(ApplyDefaults((*defaultsConfig)(nil)))
+	a.Error(ApplyDefaults(new(int)))
+}
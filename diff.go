@@ -0,0 +1,148 @@
+package opt
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+// Patch describes the changes between two values of a struct, keyed by the
+// JSON name of each field. Fields which changed have an entry and unchanged
+// fields don't. An entry holds the new value, or is empty if the field was
+// cleared. Nested structs and maps which changed hold another Patch.
+//
+// When marshaled to JSON, a Patch is a valid RFC 7396 merge patch document so
+// it can be stored in an audit log, sent to another service or applied back
+// onto the earlier value with Apply or Merge.
+type Patch map[string]Optional[any]
+
+// Diff compares `before` and `after` field by field and produces a Patch which,
+// when applied to `before`, produces `after`. `T` must be a struct or a pointer
+// to one. Field names follow the same rules as encoding/json.
+func Diff[T any](before, after T) Patch {
+	o, n := reflect.ValueOf(&before).Elem(), reflect.ValueOf(&after).Elem()
+	for o.Kind() == reflect.Pointer {
+		if o.IsNil() {
+			o = reflect.New(o.Type().Elem())
+		}
+		if n.IsNil() {
+			n = reflect.New(n.Type().Elem())
+		}
+		o, n = o.Elem(), n.Elem()
+	}
+	if o.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("opt: Diff called with non-struct type %s", o.Type()))
+	}
+
+	return diffStruct(o, n)
+}
+
+// Apply applies the patch `p` to `dst`, see Merge.
+func Apply[T any](dst *T, p Patch) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return Merge(dst, b)
+}
+
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+func diffStruct(o, n reflect.Value) Patch {
+	p := Patch{}
+	for _, f := range optreflect.Fields(o.Type(), "json") {
+		if change, ok := diffValue(o.FieldByIndex(f.Index), n.FieldByIndex(f.Index)); ok {
+			p[f.Name] = change
+		}
+	}
+	return p
+}
+
+func diffMap(o, n reflect.Value) Patch {
+	p := Patch{}
+	iter := o.MapRange()
+	for iter.Next() {
+		if !n.MapIndex(iter.Key()).IsValid() {
+			p[iter.Key().String()] = NewEmpty[any]()
+		}
+	}
+	iter = n.MapRange()
+	for iter.Next() {
+		current := o.MapIndex(iter.Key())
+		if !current.IsValid() {
+			p[iter.Key().String()] = New(iter.Value().Interface())
+			continue
+		}
+		if change, ok := diffValue(current, iter.Value()); ok {
+			p[iter.Key().String()] = change
+		}
+	}
+	return p
+}
+
+func diffValue(o, n reflect.Value) (change Optional[any], changed bool) {
+	t := n.Type()
+
+	// Types which handle their own JSON are compared as a whole, since Merge
+	// replaces them as a whole too.
+	handlesJSON := t.Implements(marshalerType) || reflect.PointerTo(t).Implements(unmarshalerType)
+
+	switch {
+	case optreflect.IsOptional(t):
+		oi, ook := optreflect.Get(o)
+		ni, nok := optreflect.Get(n)
+		switch {
+		case !ook && !nok:
+			return nil, false
+		case !nok:
+			return NewEmpty[any](), true
+		case !ook:
+			return New(ni.Interface()), true
+		}
+		return diffValue(oi, ni)
+
+	case handlesJSON:
+
+	case t.Kind() == reflect.Pointer:
+		switch {
+		case o.IsNil() && n.IsNil():
+			return nil, false
+		case n.IsNil():
+			return NewEmpty[any](), true
+		case o.IsNil():
+			return New(n.Interface()), true
+		}
+		return diffValue(o.Elem(), n.Elem())
+
+	case t.Kind() == reflect.Interface:
+		if !o.IsNil() && !n.IsNil() && o.Elem().Type() == n.Elem().Type() {
+			return diffValue(o.Elem(), n.Elem())
+		}
+
+	case t.Kind() == reflect.Struct && len(optreflect.Fields(t, "json")) == 0:
+		// There are no fields to compare one by one, such as for a struct with
+		// only unexported fields, so it's compared as a whole.
+
+	case t.Kind() == reflect.Struct:
+		if p := diffStruct(o, n); len(p) > 0 {
+			return New[any](p), true
+		}
+		return nil, false
+
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && !o.IsNil() && !n.IsNil():
+		if p := diffMap(o, n); len(p) > 0 {
+			return New[any](p), true
+		}
+		return nil, false
+	}
+
+	if reflect.DeepEqual(o.Interface(), n.Interface()) {
+		return nil, false
+	}
+	if (t.Kind() == reflect.Pointer || t.Kind() == reflect.Interface) && n.IsNil() {
+		return NewEmpty[any](), true
+	}
+	return New(n.Interface()), true
+}
@@ -0,0 +1,136 @@
+package opt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	a := assert.New(t)
+
+	before := mergeAccount{
+		ID:      "southclaws",
+		Name:    New("Southclaws"),
+		Age:     New(69),
+		Address: mergeAddress{Line1: New("1 Street"), City: New("London")},
+		Billing: New(mergeAddress{City: New("Leeds")}),
+		Tags:    []string{"a"},
+		Meta:    map[string]string{"keep": "yes", "drop": "yes", "change": "a"},
+		Created: New(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)),
+	}
+	after := before
+	after.Name = NewEmpty[string]()
+	after.Age = New(70)
+	after.Address = mergeAddress{Line1: New("1 Street"), City: New("Bristol")}
+	after.Billing = New(mergeAddress{City: New("Leeds"), Line1: New("2 Road")})
+	after.Shipping = &mergeAddress{City: New("York")}
+	after.Meta = map[string]string{"keep": "yes", "change": "b", "add": "yes"}
+	after.Extra = map[string]any{"a": 1.0}
+
+	p := Diff(before, after)
+
+	a.Equal(Patch{
+		"name":     NewEmpty[any](),
+		"age":      New[any](70),
+		"address":  New[any](Patch{"city": New[any]("Bristol")}),
+		"billing":  New[any](Patch{"line1": New[any]("2 Road")}),
+		"shipping": New[any](&mergeAddress{City: New("York")}),
+		"meta":     New[any](Patch{"drop": NewEmpty[any](), "change": New[any]("b"), "add": New[any]("yes")}),
+		"extra":    New[any](map[string]any{"a": 1.0}),
+	}, p)
+
+	b, err := json.Marshal(p)
+	a.NoError(err)
+	a.JSONEq(`{
+		"name": null,
+		"age": 70,
+		"address": {"city": "Bristol"},
+		"billing": {"line1": "2 Road"},
+		"shipping": {"line1": null, "city": "York"},
+		"meta": {"drop": null, "change": "b", "add": "yes"},
+		"extra": {"a": 1}
+	}`, string(b))
+
+	applied := before
+	a.NoError(Apply(&applied, p))
+	a.Equal(after, applied)
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	a := assert.New(t)
+
+	v := mergeAccount{
+		Name:     New("Southclaws"),
+		Billing:  New(mergeAddress{City: New("Leeds")}),
+		Shipping: &mergeAddress{City: New("York")},
+		Meta:     map[string]string{"a": "b"},
+	}
+	a.Empty(Diff(v, v))
+	a.Empty(Diff(&v, &v))
+	a.Empty(Diff(mergeAccount{}, mergeAccount{}))
+}
+
+func TestDiffCleared(t *testing.T) {
+	a := assert.New(t)
+
+	before := mergeAccount{
+		Billing:  New(mergeAddress{City: New("Leeds")}),
+		Shipping: &mergeAddress{City: New("York")},
+		Tags:     []string{"a"},
+		Meta:     map[string]string{"a": "b"},
+	}
+
+	p := Diff(before, mergeAccount{})
+	a.Equal(Patch{
+		"billing":  NewEmpty[any](),
+		"shipping": NewEmpty[any](),
+		"tags":     New[any]([]string(nil)),
+		"meta":     New[any](map[string]string(nil)),
+	}, p)
+
+	a.NoError(Apply(&before, p))
+	a.Equal(mergeAccount{}, before)
+}
+
+func TestDiffNullable(t *testing.T) {
+	a := assert.New(t)
+
+	type profile struct {
+		Nickname Nullable[string] `json:"nickname"`
+		Email    Nullable[string] `json:"email"`
+	}
+
+	before := profile{Nickname: NewNullable("southclaws"), Email: NewNullable("a@example.com")}
+	after := profile{Nickname: NewNull[string](), Email: NewNullable("b@example.com")}
+
+	p := Diff(before, after)
+	b, err := json.Marshal(p)
+	a.NoError(err)
+	a.JSONEq(`{"nickname": null, "email": "b@example.com"}`, string(b))
+
+	applied := before
+	a.NoError(Apply(&applied, p))
+	a.Equal(after, applied)
+	a.True(applied.Nickname.IsNull())
+}
+
+func TestDiffOpaqueStruct(t *testing.T) {
+	a := assert.New(t)
+
+	type money struct{ cents int }
+	type account struct {
+		Balance money `json:"balance"`
+	}
+
+	p := Diff(account{Balance: money{1}}, account{Balance: money{2}})
+	a.Equal(Patch{"balance": New[any](money{2})}, p, "structs without exported fields are compared as a whole")
+
+	a.Empty(Diff(account{Balance: money{1}}, account{Balance: money{1}}))
+}
+
+func TestDiffNonStruct(t *testing.T) {
+	assert.Panics(t, func() { Diff(1, 2) })
+}
@@ -0,0 +1,64 @@
+package opt
+
+import (
+	"reflect"
+
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+// Flag is an optional which implements flag.Value and flag.Getter. Unlike the
+// standard flag types it stays empty unless the flag is actually passed, so it's
+// possible to tell "not passed" apart from "passed the default value". This
+// makes it easy to layer command-line flags over other sources of config.
+//
+//	var port opt.Optional[int]
+//	flag.Var(opt.NewFlag(&port), "port", "port to listen on")
+//
+// Values are parsed in the same way as UnmarshalText. If `T` is a bool the
+// flag may be passed without a value, such as `-verbose`.
+type Flag[T any] Optional[T]
+
+// NewFlag returns a Flag which sets the optional pointed to by `o`.
+func NewFlag[T any](o *Optional[T]) *Flag[T] {
+	return (*Flag[T])(o)
+}
+
+// Optional converts this into a plain optional.
+func (f Flag[T]) Optional() Optional[T] {
+	return Optional[T](f)
+}
+
+// String formats the value of the flag, or returns an empty string if it
+// wasn't passed.
+func (f *Flag[T]) String() string {
+	if f == nil || *f == nil {
+		return ""
+	}
+	text, err := (*f).Optional().MarshalText()
+	if err != nil {
+		return ""
+	}
+	return string(text)
+}
+
+// Set parses `value` into the flag, making it present. It's called by the flag
+// package each time the flag is passed, so the last value wins.
+func (f *Flag[T]) Set(value string) error {
+	var v T
+	if err := optreflect.ParseText(reflect.ValueOf(&v).Elem(), value); err != nil {
+		return err
+	}
+	*f = Flag[T]{v}
+	return nil
+}
+
+// Get returns the flag's value as an Optional[T], see flag.Getter.
+func (f *Flag[T]) Get() any {
+	return f.Optional()
+}
+
+// IsBoolFlag reports whether the flag can be passed without a value.
+func (f *Flag[T]) IsBoolFlag() bool {
+	var v T
+	return reflect.TypeOf(&v).Elem().Kind() == reflect.Bool
+}
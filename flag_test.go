@@ -0,0 +1,58 @@
+package opt
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlag(t *testing.T) {
+	a := assert.New(t)
+
+	var (
+		port    Optional[int]
+		host    Optional[string]
+		verbose Optional[bool]
+		ratio   Optional[float64]
+	)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(NewFlag(&port), "port", "")
+	fs.Var(NewFlag(&host), "host", "")
+	fs.Var(NewFlag(&verbose), "verbose", "")
+	fs.Var(NewFlag(&ratio), "ratio", "")
+
+	a.NoError(fs.Parse([]string{"-port", "0", "-verbose", "-port=8080", "-host="}))
+
+	a.Equal(New(8080), port)
+	a.Equal(New(""), host, "passed an empty value")
+	a.Equal(New(true), verbose)
+	a.Empty(ratio, "not passed")
+
+	a.Equal("8080", fs.Lookup("port").Value.String())
+	a.Equal("", fs.Lookup("ratio").Value.String())
+	a.Equal(New(8080), fs.Lookup("port").Value.(flag.Getter).Get())
+	a.Equal(NewEmpty[float64](), fs.Lookup("ratio").Value.(flag.Getter).Get())
+
+	a.Error(fs.Parse([]string{"-port", "http"}))
+	a.Equal(New(8080), port)
+}
+
+func TestFlagDefaults(t *testing.T) {
+	a := assert.New(t)
+
+	var port Optional[int]
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(NewFlag(&port), "port", "port to listen on")
+
+	var usage []string
+	fs.VisitAll(func(f *flag.Flag) { usage = append(usage, f.Name+"="+f.DefValue) })
+	a.Equal([]string{"port="}, usage)
+
+	var nilFlag *Flag[int]
+	a.Equal("", nilFlag.String())
+	a.False(nilFlag.IsBoolFlag())
+}
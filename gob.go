@@ -0,0 +1,50 @@
+package opt
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+)
+
+// GobEncode encodes the optional as a presence marker followed by the gob
+// encoding of the wrapped value. This keeps an empty optional distinct from a
+// present zero value, which gob would otherwise treat the same.
+func (o Optional[T]) GobEncode() ([]byte, error) {
+	if o == nil {
+		return []byte{0}, nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(1)
+	err := gob.NewEncoder(&buf).Encode(&o[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode decodes data produced by GobEncode into this optional.
+func (o *Optional[T]) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("opt: gob data is missing the presence marker")
+	}
+
+	switch data[0] {
+	case 0:
+		*o = NewEmpty[T]()
+		return nil
+
+	case 1:
+		var v T
+		err := gob.NewDecoder(bytes.NewReader(data[1:])).Decode(&v)
+		if err != nil {
+			return err
+		}
+
+		*o = New(v)
+		return nil
+	}
+
+	return fmt.Errorf("opt: invalid gob presence marker %d", data[0])
+}
@@ -0,0 +1,54 @@
+package opt
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGob(t *testing.T) {
+	a := assert.New(t)
+
+	type Address struct {
+		City string
+	}
+	type Data struct {
+		ID      string
+		Name    Optional[string]
+		Age     Optional[int]
+		Address Optional[Address]
+		Any     any
+	}
+
+	gob.Register(Optional[int]{})
+
+	tests := []Data{
+		{ID: "empty"},
+		{ID: "zero", Name: New(""), Age: New(0), Address: New(Address{})},
+		{ID: "present", Name: New("Southclaws"), Age: New(69), Address: New(Address{City: "London"})},
+		{ID: "interface empty", Any: NewEmpty[int]()},
+		{ID: "interface present", Any: New(0)},
+	}
+
+	for _, in := range tests {
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(in)
+		a.NoError(err, in.ID)
+
+		var out Data
+		err = gob.NewDecoder(&buf).Decode(&out)
+		a.NoError(err, in.ID)
+		a.Equal(in, out, in.ID)
+	}
+}
+
+func TestGobDecodeInvalid(t *testing.T) {
+	a := assert.New(t)
+
+	var o Optional[int]
+	a.Error(o.GobDecode(nil))
+	a.Error(o.GobDecode([]byte{2}))
+	a.Error(o.GobDecode([]byte{1, 0xff}))
+}
@@ -0,0 +1,71 @@
+package opt
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// gqlMarshaler and gqlUnmarshaler match the interfaces which gqlgen uses for
+// custom scalars, so `T` may be a custom scalar itself.
+type (
+	gqlMarshaler   interface{ MarshalGQL(w io.Writer) }
+	gqlUnmarshaler interface{ UnmarshalGQL(v any) error }
+)
+
+// MarshalGQL writes the wrapped value as a GraphQL scalar, implementing
+// graphql.Marshaler from gqlgen. An empty optional is written as `null`. If `T`
+// or `*T` is a gqlgen scalar then its MarshalGQL is used, otherwise the value is
+// written as JSON.
+//
+// As MarshalGQL can't return an error, a value which fails to marshal to JSON
+// is written as `null`.
+func (o Optional[T]) MarshalGQL(w io.Writer) {
+	if o == nil {
+		io.WriteString(w, "null")
+		return
+	}
+
+	if m, ok := any(&o[0]).(gqlMarshaler); ok {
+		m.MarshalGQL(w)
+		return
+	}
+
+	b, err := json.Marshal(&o[0])
+	if err != nil {
+		io.WriteString(w, "null")
+		return
+	}
+	w.Write(b)
+}
+
+// UnmarshalGQL unmarshals a GraphQL input value into a value wrapped by this
+// optional, implementing graphql.Unmarshaler from gqlgen. A nil input produces
+// an empty optional. If `*T` is a gqlgen scalar then its UnmarshalGQL is used,
+// otherwise the input, which gqlgen provides as a JSON-like value such as an
+// int64, string or map[string]any, is converted to `T` through JSON.
+func (o *Optional[T]) UnmarshalGQL(v any) error {
+	if v == nil {
+		*o = NewEmpty[T]()
+		return nil
+	}
+
+	var value T
+	if u, ok := any(&value).(gqlUnmarshaler); ok {
+		if err := u.UnmarshalGQL(v); err != nil {
+			return err
+		}
+		*o = New(value)
+		return nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &value); err != nil {
+		return err
+	}
+
+	*o = New(value)
+	return nil
+}
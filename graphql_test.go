@@ -0,0 +1,87 @@
+package opt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gqlCurrency is a custom scalar in the same style as a gqlgen one.
+type gqlCurrency struct{ code string }
+
+func (c *gqlCurrency) MarshalGQL(w io.Writer) { fmt.Fprintf(w, "%q", strings.ToUpper(c.code)) }
+
+func (c *gqlCurrency) UnmarshalGQL(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("currency must be a string")
+	}
+	c.code = strings.ToLower(s)
+	return nil
+}
+
+func marshalGQL(m interface{ MarshalGQL(io.Writer) }) string {
+	var buf bytes.Buffer
+	m.MarshalGQL(&buf)
+	return buf.String()
+}
+
+func TestMarshalGQL(t *testing.T) {
+	a := assert.New(t)
+
+	type Point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+
+	a.Equal("null", marshalGQL(NewEmpty[string]()))
+	a.Equal(`"Southclaws"`, marshalGQL(New("Southclaws")))
+	a.Equal("0", marshalGQL(New(0)))
+	a.Equal("false", marshalGQL(New(false)))
+	a.Equal(`{"x":1,"y":2}`, marshalGQL(New(Point{1, 2})))
+	a.Equal(`"2023-01-02T03:04:05Z"`, marshalGQL(New(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC))))
+	a.Equal(`"GBP"`, marshalGQL(New(gqlCurrency{"gbp"})))
+	a.Equal("null", marshalGQL(New(func() {})))
+}
+
+func TestUnmarshalGQL(t *testing.T) {
+	a := assert.New(t)
+
+	type Point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+
+	s := New("value")
+	a.NoError(s.UnmarshalGQL(nil))
+	a.Empty(s)
+
+	a.NoError(s.UnmarshalGQL("Southclaws"))
+	a.Equal(New("Southclaws"), s)
+
+	var i Optional[int]
+	a.NoError(i.UnmarshalGQL(int64(69)))
+	a.Equal(New(69), i)
+	a.NoError(i.UnmarshalGQL(json.Number("0")))
+	a.Equal(New(0), i)
+	a.Error(i.UnmarshalGQL("old"))
+
+	var p Optional[Point]
+	a.NoError(p.UnmarshalGQL(map[string]any{"x": int64(1), "y": 2.0}))
+	a.Equal(New(Point{1, 2}), p)
+
+	var ts Optional[time.Time]
+	a.NoError(ts.UnmarshalGQL("2023-01-02T03:04:05Z"))
+	a.Equal(New(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)), ts)
+
+	var c Optional[gqlCurrency]
+	a.NoError(c.UnmarshalGQL("GBP"))
+	a.Equal(New(gqlCurrency{"gbp"}), c)
+	a.Error(c.UnmarshalGQL(1))
+}
@@ -0,0 +1,87 @@
+package optreflect
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Field describes an exported struct field as seen through a struct tag.
+type Field struct {
+	reflect.StructField
+
+	// Name is the name from the tag, or the Go field name if there's no tag.
+	Name string
+
+	// Tagged is true if the field had a tag with a name.
+	Tagged bool
+
+	// Options holds the comma-separated options which followed the name.
+	Options string
+
+	// Index is the index sequence for reflect.Value.FieldByIndex, which takes
+	// embedded structs into account.
+	Index []int
+}
+
+// HasOption reports whether the tag options contain `option`.
+func (f Field) HasOption(option string) bool {
+	for _, o := range strings.Split(f.Options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// Fields lists the fields of the struct type `t` using the names from the tag
+// `key`, following the same rules as encoding/json. Unexported fields and those
+// tagged "-" are skipped and embedded structs without a tag name are flattened
+// into the outer struct, with outer fields taking precedence.
+func Fields(t reflect.Type, key string) []Field {
+	var fields []Field
+	seen := map[string]bool{}
+	walkFields(t, key, nil, &fields, seen)
+	return fields
+}
+
+func walkFields(t reflect.Type, key string, index []int, fields *[]Field, seen map[string]bool) {
+	var embedded []reflect.StructField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(key)
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			embedded = append(embedded, f)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		tagged := name != ""
+		if !tagged {
+			name = f.Name
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		*fields = append(*fields, Field{
+			StructField: f,
+			Name:        name,
+			Tagged:      tagged,
+			Options:     options,
+			Index:       append(append([]int{}, index...), i),
+		})
+	}
+
+	for _, f := range embedded {
+		walkFields(f.Type, key, append(append([]int{}, index...), f.Index...), fields, seen)
+	}
+}
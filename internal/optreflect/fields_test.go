@@ -0,0 +1,44 @@
+package optreflect
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Embedded struct {
+	Shared string `json:"shared"`
+	Inner  string `json:"inner,omitempty"`
+}
+
+type Outer struct {
+	Embedded
+	Name    string `json:"name"`
+	Shared  string `json:"shared"`
+	Plain   string
+	Skipped string `json:"-"`
+	hidden  string
+}
+
+func TestFields(t *testing.T) {
+	a := assert.New(t)
+
+	fields := Fields(reflect.TypeOf(Outer{}), "json")
+
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+	a.Equal([]string{"name", "shared", "Plain", "inner"}, names)
+
+	a.True(fields[0].Tagged)
+	a.False(fields[2].Tagged)
+	a.Equal([]int{2}, fields[1].Index, "outer field wins over embedded")
+	a.Equal([]int{0, 1}, fields[3].Index)
+	a.True(fields[3].HasOption("omitempty"))
+	a.False(fields[0].HasOption("omitempty"))
+
+	v := reflect.ValueOf(Outer{Embedded: Embedded{Inner: "inner"}})
+	a.Equal("inner", v.FieldByIndex(fields[3].Index).Interface())
+}
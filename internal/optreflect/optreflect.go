@@ -0,0 +1,46 @@
+// Package optreflect contains reflection helpers shared by the opt packages for
+// working with optional values whose type parameter isn't known statically.
+package optreflect
+
+import (
+	"reflect"
+	"strings"
+)
+
+const pkgPath = "github.com/Southclaws/opt"
+
+// TypeName returns the name of `t` without its type arguments, such as
+// "Nullable" for `opt.Nullable[int]`, if `t` is declared in the opt package.
+func TypeName(t reflect.Type) (name string, ok bool) {
+	if t.PkgPath() != pkgPath {
+		return "", false
+	}
+	name, _, _ = strings.Cut(t.Name(), "[")
+	return name, true
+}
+
+// IsOptional reports whether `t` is an instantiation of `opt.Optional`.
+func IsOptional(t reflect.Type) bool {
+	name, ok := TypeName(t)
+	return ok && t.Kind() == reflect.Slice && name == "Optional"
+}
+
+// IsNullable reports whether `t` is an instantiation of `opt.Nullable`, whose
+// single element, if set, is an `opt.Optional`.
+func IsNullable(t reflect.Type) bool {
+	name, ok := TypeName(t)
+	return ok && t.Kind() == reflect.Slice && name == "Nullable" && IsOptional(t.Elem())
+}
+
+// Get returns the value wrapped by the optional `v`, `ok` signals existence.
+func Get(v reflect.Value) (value reflect.Value, ok bool) {
+	if v.Len() == 0 {
+		return
+	}
+	return v.Index(0), true
+}
+
+// Set wraps `value` in the optional `v`, making it present.
+func Set(v, value reflect.Value) {
+	v.Set(reflect.Append(reflect.Zero(v.Type()), value))
+}
@@ -0,0 +1,55 @@
+package optreflect
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// optional mirrors the shape of `opt.Optional` without the import cycle, the
+// package path check is exercised by the packages which use these helpers.
+type optional[T any] []T
+
+func TestIsOptional(t *testing.T) {
+	a := assert.New(t)
+
+	a.False(IsOptional(reflect.TypeOf(optional[int]{})))
+	a.False(IsOptional(reflect.TypeOf([]int{})))
+	a.False(IsOptional(reflect.TypeOf(0)))
+}
+
+func TestTypeName(t *testing.T) {
+	a := assert.New(t)
+
+	_, ok := TypeName(reflect.TypeOf(optional[int]{}))
+	a.False(ok, "only types declared in opt have a name")
+	_, ok = TypeName(reflect.TypeOf(0))
+	a.False(ok)
+}
+
+func TestIsNullable(t *testing.T) {
+	a := assert.New(t)
+
+	a.False(IsNullable(reflect.TypeOf([]optional[int]{})))
+	a.False(IsNullable(reflect.TypeOf(0)))
+}
+
+func TestGet(t *testing.T) {
+	a := assert.New(t)
+
+	v, ok := Get(reflect.ValueOf(optional[int]{5}))
+	a.True(ok)
+	a.Equal(5, v.Interface())
+
+	_, ok = Get(reflect.ValueOf(optional[int](nil)))
+	a.False(ok)
+}
+
+func TestSet(t *testing.T) {
+	a := assert.New(t)
+
+	var o optional[int]
+	Set(reflect.ValueOf(&o).Elem(), reflect.ValueOf(5))
+	a.Equal(optional[int]{5}, o)
+}
@@ -0,0 +1,67 @@
+package optreflect
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type level string
+
+func TestParseText(t *testing.T) {
+	a := assert.New(t)
+
+	var s level
+	a.NoError(ParseText(reflect.ValueOf(&s).Elem(), "debug"))
+	a.Equal(level("debug"), s)
+
+	var i int8
+	a.NoError(ParseText(reflect.ValueOf(&i).Elem(), "-12"))
+	a.Equal(int8(-12), i)
+	a.Error(ParseText(reflect.ValueOf(&i).Elem(), "1000"))
+
+	var u uint
+	a.NoError(ParseText(reflect.ValueOf(&u).Elem(), "12"))
+	a.Equal(uint(12), u)
+
+	var f float64
+	a.NoError(ParseText(reflect.ValueOf(&f).Elem(), "1.5"))
+	a.Equal(1.5, f)
+
+	var b bool
+	a.NoError(ParseText(reflect.ValueOf(&b).Elem(), "true"))
+	a.True(b)
+
+	var ip net.IP
+	a.NoError(ParseText(reflect.ValueOf(&ip).Elem(), "127.0.0.1"))
+	a.Equal("127.0.0.1", ip.String())
+
+	var st struct{}
+	a.Error(ParseText(reflect.ValueOf(&st).Elem(), "{}"))
+}
+
+func TestFormatText(t *testing.T) {
+	a := assert.New(t)
+
+	for _, test := range []struct {
+		Value any
+		Text  string
+	}{
+		{level("debug"), "debug"},
+		{int8(-12), "-12"},
+		{uint(12), "12"},
+		{1.5, "1.5"},
+		{float32(2.1), "2.1"},
+		{true, "true"},
+		{net.IPv4(127, 0, 0, 1), "127.0.0.1"},
+	} {
+		s, err := FormatText(reflect.ValueOf(test.Value))
+		a.NoError(err)
+		a.Equal(test.Text, s)
+	}
+
+	_, err := FormatText(reflect.ValueOf(struct{}{}))
+	a.Error(err)
+}
@@ -0,0 +1,166 @@
+//go:build goexperiment.jsonv2
+
+package opt
+
+import (
+	"encoding/json"
+	"encoding/json/jsontext"
+	jsonv2 "encoding/json/v2"
+)
+
+// MarshalJSONTo marshals the value being wrapped directly to the encoder. If
+// there is no value being wrapped, `null` is written. Options set on the
+// encoder, such as those for the outer Marshal call, apply to the value.
+func (o Optional[T]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if o == nil {
+		return enc.WriteToken(jsontext.Null)
+	}
+	return jsonv2.MarshalEncode(enc, &o[0])
+}
+
+// UnmarshalJSONFrom unmarshals the next value from the decoder into a value
+// wrapped by this optional. A `null` produces an empty optional. Options set on
+// the decoder, such as rejecting unknown members, apply to the value.
+func (o *Optional[T]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	if dec.PeekKind() == 'n' {
+		_, err := dec.ReadToken()
+		if err != nil {
+			return err
+		}
+
+		*o = NewEmpty[T]()
+		return nil
+	}
+
+	var v T
+	err := jsonv2.UnmarshalDecode(dec, &v)
+	if err != nil {
+		return err
+	}
+
+	*o = New(v)
+	return nil
+}
+
+// MarshalJSONTo marshals the nullable directly to the encoder, see
+// Optional.MarshalJSONTo. Both unset and null are written as `null`.
+func (n Nullable[T]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return n.Optional().MarshalJSONTo(enc)
+}
+
+// UnmarshalJSONFrom unmarshals the next value from the decoder into this
+// nullable, see Optional.UnmarshalJSONFrom. A `null` produces an explicit null.
+func (n *Nullable[T]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	var o Optional[T]
+	if err := o.UnmarshalJSONFrom(dec); err != nil {
+		return err
+	}
+	*n = Nullable[T]{o}
+	return nil
+}
+
+// MarshalJSONTo writes the wrapped value to the encoder as a JSON string, see
+// StringEncoded.MarshalJSON.
+func (s StringEncoded[T]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if s == nil {
+		return enc.WriteToken(jsontext.Null)
+	}
+	b, err := json.Marshal(s[0])
+	if err != nil {
+		return err
+	}
+	return enc.WriteToken(jsontext.String(string(b)))
+}
+
+// UnmarshalJSONFrom reads a JSON string containing a number or bool from the
+// decoder, see StringEncoded.UnmarshalJSON.
+func (s *StringEncoded[T]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	if dec.PeekKind() != '"' {
+		// Let UnmarshalJSON produce the same result and error for anything
+		// which isn't a string, such as `null`.
+		raw, err := dec.ReadValue()
+		if err != nil {
+			return err
+		}
+		return s.UnmarshalJSON(raw)
+	}
+
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return err
+	}
+	v, err := parseStringEncoded[T](tok.String())
+	if err != nil {
+		return err
+	}
+	*s = StringEncoded[T]{v}
+	return nil
+}
+
+// MarshalJSONTo marshals the wrapped value, see Optional.MarshalJSONTo.
+func (e EmptyWhen[T, P]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return e.Optional().MarshalJSONTo(enc)
+}
+
+// UnmarshalJSONFrom unmarshals like Optional.UnmarshalJSONFrom then applies the
+// policy.
+func (e *EmptyWhen[T, P]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	var p P
+	return decodeJSONFromIf((*Optional[T])(e), dec, p.IsEmpty)
+}
+
+// MarshalJSONTo marshals the wrapped value, see Optional.MarshalJSONTo.
+func (b BlankAsEmpty[T]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return b.Optional().MarshalJSONTo(enc)
+}
+
+// UnmarshalJSONFrom unmarshals like Optional.UnmarshalJSONFrom then discards
+// blanks.
+func (b *BlankAsEmpty[T]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return decodeJSONFromIf((*Optional[T])(b), dec, Blank[T]{}.IsEmpty)
+}
+
+// MarshalJSONTo marshals the wrapped value, see Optional.MarshalJSONTo.
+func (z ZeroAsEmpty[T]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return z.Optional().MarshalJSONTo(enc)
+}
+
+// UnmarshalJSONFrom unmarshals like Optional.UnmarshalJSONFrom then discards
+// zeros.
+func (z *ZeroAsEmpty[T]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return decodeJSONFromIf((*Optional[T])(z), dec, Zero[T]{}.IsEmpty)
+}
+
+func decodeJSONFromIf[T any](o *Optional[T], dec *jsontext.Decoder, empty func(T) bool) error {
+	var v Optional[T]
+	if err := v.UnmarshalJSONFrom(dec); err != nil {
+		return err
+	}
+	*o = discardIf(v, empty)
+	return nil
+}
+
+// MarshalJSONTo marshals the wrapped value, see Optional.MarshalJSONTo.
+func (l Lenient[T]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return l.value.MarshalJSONTo(enc)
+}
+
+// UnmarshalJSONFrom unmarshals like Optional.UnmarshalJSONFrom, using the
+// decoder's options, except that a failure produces an empty optional and is
+// recorded instead of being returned. Only malformed JSON, which leaves the
+// decoder unable to continue, is returned.
+func (l *Lenient[T]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	l.value, l.err = nil, nil
+
+	// The whole value is read first so that a failure part-way through it
+	// doesn't leave the rest of it in the decoder.
+	raw, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+
+	if err := jsonv2.Unmarshal(raw, &l.value, dec.Options()); err != nil {
+		l.value, l.err = nil, err
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+//go:build goexperiment.jsonv2
+
+package opt
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"encoding/json/jsontext"
+	jsonv2 "encoding/json/v2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONv2(t *testing.T) {
+	a := assert.New(t)
+
+	type Data struct {
+		ID   string           `json:"id"`
+		Name Optional[string] `json:"name"`
+		Age  Optional[int]    `json:"age,omitzero"`
+		Nick Optional[string] `json:"nick,omitempty"`
+	}
+
+	b1, err := jsonv2.Marshal(Data{ID: "southclaws"})
+	a.NoError(err)
+	a.Equal(`{"id":"southclaws","name":null}`, string(b1))
+
+	b2, err := jsonv2.Marshal(Data{ID: "southclaws", Name: New("Southclaws"), Age: New(0), Nick: New("")})
+	a.NoError(err)
+	a.Equal(`{"id":"southclaws","name":"Southclaws","age":0}`, string(b2))
+
+	var out Data
+	a.NoError(jsonv2.Unmarshal(b2, &out))
+	a.Equal(New("Southclaws"), out.Name)
+	a.Equal(New(0), out.Age)
+
+	a.NoError(jsonv2.Unmarshal([]byte(`{"id":"southclaws","name":null}`), &out))
+	a.Empty(out.Name)
+
+	a.Error(jsonv2.Unmarshal([]byte(`{"age":"old"}`), &out))
+}
+
+func TestJSONv2Options(t *testing.T) {
+	a := assert.New(t)
+
+	type Inner struct {
+		Known string `json:"known"`
+	}
+	type Outer struct {
+		Inner Optional[Inner] `json:"inner"`
+	}
+
+	var out Outer
+	err := jsonv2.Unmarshal([]byte(`{"inner":{"known":"a","unknown":"b"}}`), &out, jsonv2.RejectUnknownMembers(true))
+	a.Error(err, "options on the outer call apply to the wrapped value")
+
+	var buf bytes.Buffer
+	enc := jsontext.NewEncoder(&buf, jsontext.Multiline(true))
+	a.NoError(New(Inner{Known: "a"}).MarshalJSONTo(enc))
+	a.Equal("{\n\t\"known\": \"a\"\n}\n", buf.String())
+}
+
+func TestJSONDecoderSettings(t *testing.T) {
+	a := assert.New(t)
+
+	type Inner struct {
+		Known string `json:"known"`
+		Any   any    `json:"any"`
+	}
+	type Outer struct {
+		Inner Optional[Inner] `json:"inner"`
+	}
+
+	dec := json.NewDecoder(strings.NewReader(`{"inner":{"known":"a","unknown":"b"}}`))
+	dec.DisallowUnknownFields()
+	var out Outer
+	a.EqualError(dec.Decode(&out), `json: unknown field "unknown"`)
+
+	dec = json.NewDecoder(strings.NewReader(`{"inner":{"any":1.5}}`))
+	dec.UseNumber()
+	out = Outer{}
+	a.NoError(dec.Decode(&out))
+	a.Equal(json.Number("1.5"), out.Inner.OrZero().Any)
+}
+
+func TestJSONStringOption(t *testing.T) {
+	a := assert.New(t)
+
+	type Data struct {
+		ID     Optional[int64] `json:"id,string"`
+		Parent Optional[int64] `json:"parent,string"`
+	}
+
+	b, err := json.Marshal(Data{ID: New[int64](9007199254740993)})
+	a.NoError(err)
+	a.Equal(`{"id":"9007199254740993","parent":null}`, string(b))
+
+	var out Data
+	a.NoError(json.Unmarshal(b, &out))
+	a.Equal(New[int64](9007199254740993), out.ID)
+	a.Empty(out.Parent)
+
+	b, err = jsonv2.Marshal(Data{ID: New[int64](5)})
+	a.NoError(err)
+	a.Equal(`{"id":"5","parent":null}`, string(b))
+
+	b, err = jsonv2.Marshal(struct {
+		ID StringEncoded[int64] `json:"id"`
+	}{NewStringEncoded[int64](5)})
+	a.NoError(err)
+	a.Equal(`{"id":"5"}`, string(b))
+}
+
+func TestJSONDecoderSettingsWrappers(t *testing.T) {
+	a := assert.New(t)
+
+	type Inner struct {
+		Known string `json:"known"`
+		Any   any    `json:"any"`
+	}
+	type Comparable struct {
+		Known string `json:"known"`
+	}
+	type Outer struct {
+		Nullable Nullable[Inner]                         `json:"nullable"`
+		Zero     ZeroAsEmpty[Comparable]                 `json:"zero"`
+		When     EmptyWhen[Comparable, Zero[Comparable]] `json:"when"`
+		Blank    BlankAsEmpty[string]                    `json:"blank"`
+		Encoded  StringEncoded[int64]                    `json:"encoded"`
+		Lenient  Lenient[Inner]                          `json:"lenient"`
+	}
+
+	for _, field := range []string{"nullable", "zero", "when"} {
+		dec := json.NewDecoder(strings.NewReader(`{"` + field + `":{"known":"a","unknown":"b"}}`))
+		dec.DisallowUnknownFields()
+		a.EqualError(dec.Decode(&Outer{}), `json: unknown field "unknown"`, field)
+
+		var typeErr *json.UnmarshalTypeError
+		err := json.Unmarshal([]byte(`{"`+field+`":{"known":5}}`), &Outer{})
+		a.ErrorAs(err, &typeErr, field)
+		a.Equal(field+".known", typeErr.Field, field)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	err := json.Unmarshal([]byte(`{"blank":5}`), &Outer{})
+	a.ErrorAs(err, &typeErr)
+	a.Equal("blank", typeErr.Field)
+
+	err = json.Unmarshal([]byte(`{"encoded":"x"}`), &Outer{})
+	a.ErrorContains(err, "encoded")
+	a.ErrorContains(err, "string encoded optional")
+
+	dec := json.NewDecoder(strings.NewReader(`{"lenient":{"known":"a","unknown":"b"},"blank":" "}`))
+	dec.DisallowUnknownFields()
+	var out Outer
+	a.NoError(dec.Decode(&out))
+	a.Empty(out.Lenient.Optional())
+	a.ErrorContains(out.Lenient.Err(), "unknown")
+	a.Empty(out.Blank)
+
+	dec = json.NewDecoder(strings.NewReader(`{"nullable":{"any":1.5},"lenient":{"any":1.5},"encoded":"5","zero":{"known":""}}`))
+	dec.UseNumber()
+	out = Outer{}
+	a.NoError(dec.Decode(&out))
+	a.Equal(json.Number("1.5"), out.Nullable.Optional().OrZero().Any)
+	a.Equal(json.Number("1.5"), out.Lenient.Optional().OrZero().Any)
+	a.Equal(NewStringEncoded[int64](5), out.Encoded)
+	a.Empty(out.Zero)
+
+	a.NoError(json.Unmarshal([]byte(`{"nullable":null}`), &out))
+	a.True(out.Nullable.IsNull())
+
+	b, err := jsonv2.Marshal(Outer{Nullable: NewNull[Inner](), Encoded: NewStringEncoded[int64](5), Lenient: NewLenient(Inner{Known: "a"})})
+	a.NoError(err)
+	a.Equal(`{"nullable":null,"zero":null,"when":null,"blank":null,"encoded":"5","lenient":{"known":"a","any":null}}`, string(b))
+}
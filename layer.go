@@ -0,0 +1,142 @@
+package opt
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+// Source is a named layer of configuration for Layer, such as the defaults, a
+// config file, the environment or command-line flags.
+type Source[T any] struct {
+	Name  string
+	Value T
+}
+
+// NewSource creates a named source for Layer.
+func NewSource[T any](name string, value T) Source[T] {
+	return Source[T]{Name: name, Value: value}
+}
+
+// Provenance maps the path of each field set by Layer, such as "db.url", to the
+// name of the source which supplied its value.
+type Provenance map[string]string
+
+// String lists each field and its source, one per line, sorted by path. It's
+// intended for debugging output such as a `--print-config` command.
+func (p Provenance) String() string {
+	paths := make([]string, 0, len(p))
+	for path := range p {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var sb strings.Builder
+	for _, path := range paths {
+		fmt.Fprintf(&sb, "%s: %s\n", path, p[path])
+	}
+	return sb.String()
+}
+
+// Layer deep-merges each of the `sources` onto `dst` in order, so later sources
+// take precedence over earlier ones. `T` must be a struct.
+//
+//	var cfg Config
+//	prov := opt.Layer(&cfg,
+//		opt.NewSource("defaults", defaults),
+//		opt.NewSource("file", fromFile),
+//		opt.NewSource("env", fromEnv),
+//		opt.NewSource("flags", fromFlags),
+//	)
+//
+// Present optional fields replace the destination's value and empty ones are
+// skipped. Nested structs, pointers to structs and optional structs which are
+// already present in `dst` are merged field by field. Any other field is
+// replaced only if the source's value isn't the zero value. Structs which
+// implement encoding.TextUnmarshaler or json.Unmarshaler, such as time.Time,
+// are treated as a single value.
+//
+// The returned Provenance records which source supplied each field. Paths use
+// the same field names as encoding/json.
+func Layer[T any](dst *T, sources ...Source[T]) Provenance {
+	d := reflect.ValueOf(dst).Elem()
+	if d.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("opt: Layer called with non-struct type %s", d.Type()))
+	}
+
+	p := Provenance{}
+	for _, s := range sources {
+		layerStruct(d, reflect.ValueOf(&s.Value).Elem(), s.Name, "", p)
+	}
+	return p
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// isLayerable reports whether `t` is a struct to merge field by field rather
+// than a single value.
+func isLayerable(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct &&
+		!reflect.PointerTo(t).Implements(unmarshalerType) &&
+		!reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func layerStruct(dst, src reflect.Value, name, path string, p Provenance) {
+	for _, f := range optreflect.Fields(dst.Type(), "json") {
+		fieldPath := f.Name
+		if path != "" {
+			fieldPath = path + "." + f.Name
+		}
+		layerValue(dst.FieldByIndex(f.Index), src.FieldByIndex(f.Index), name, fieldPath, p)
+	}
+}
+
+func layerValue(dst, src reflect.Value, name, path string, p Provenance) {
+	t := dst.Type()
+
+	switch {
+	case optreflect.IsOptional(t):
+		s, ok := optreflect.Get(src)
+		if !ok {
+			return
+		}
+		if d, ok := optreflect.Get(dst); ok && isLayerable(t.Elem()) {
+			// Merge into a copy so the destination never writes through to
+			// memory it may share with a source.
+			merged := reflect.New(t.Elem()).Elem()
+			merged.Set(d)
+			layerStruct(merged, s, name, path, p)
+			optreflect.Set(dst, merged)
+			return
+		}
+		optreflect.Set(dst, s)
+
+	case isLayerable(t):
+		layerStruct(dst, src, name, path, p)
+		return
+
+	case t.Kind() == reflect.Pointer && isLayerable(t.Elem()):
+		if src.IsNil() {
+			return
+		}
+		merged := reflect.New(t.Elem())
+		if !dst.IsNil() {
+			merged.Elem().Set(dst.Elem())
+		}
+		layerStruct(merged.Elem(), src.Elem(), name, path, p)
+		dst.Set(merged)
+		return
+
+	default:
+		if src.IsZero() {
+			return
+		}
+		dst.Set(src)
+	}
+
+	p[path] = name
+}
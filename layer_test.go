@@ -0,0 +1,129 @@
+package opt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type layerDatabase struct {
+	URL     Optional[string] `json:"url"`
+	MaxConn Optional[int]    `json:"max_conn"`
+}
+
+type layerConfig struct {
+	Port     Optional[int]           `json:"port"`
+	Host     Optional[string]        `json:"host"`
+	Debug    bool                    `json:"debug"`
+	Started  Optional[time.Time]     `json:"started"`
+	Database layerDatabase           `json:"db"`
+	Cache    *layerDatabase          `json:"cache"`
+	Replica  Optional[layerDatabase] `json:"replica"`
+	Tags     []string                `json:"tags"`
+}
+
+func TestLayer(t *testing.T) {
+	a := assert.New(t)
+
+	started := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	defaults := layerConfig{
+		Port: New(8080),
+		Host: New("localhost"),
+		Database: layerDatabase{
+			URL:     New("postgres://localhost"),
+			MaxConn: New(10),
+		},
+		Replica: New(layerDatabase{URL: New("postgres://replica")}),
+	}
+	file := layerConfig{
+		Host:     New("0.0.0.0"),
+		Database: layerDatabase{MaxConn: New(20)},
+		Cache:    &layerDatabase{URL: New("redis://cache")},
+		Replica:  New(layerDatabase{MaxConn: New(5)}),
+		Tags:     []string{"a"},
+	}
+	env := layerConfig{
+		Port:    New(0),
+		Started: New(started),
+		Cache:   &layerDatabase{MaxConn: New(3)},
+	}
+	flags := layerConfig{
+		Debug: true,
+	}
+
+	var cfg layerConfig
+	prov := Layer(&cfg,
+		NewSource("defaults", defaults),
+		NewSource("file", file),
+		NewSource("env", env),
+		NewSource("flags", flags),
+	)
+
+	a.Equal(layerConfig{
+		Port:    New(0),
+		Host:    New("0.0.0.0"),
+		Debug:   true,
+		Started: New(started),
+		Database: layerDatabase{
+			URL:     New("postgres://localhost"),
+			MaxConn: New(20),
+		},
+		Cache: &layerDatabase{
+			URL:     New("redis://cache"),
+			MaxConn: New(3),
+		},
+		Replica: New(layerDatabase{
+			URL:     New("postgres://replica"),
+			MaxConn: New(5),
+		}),
+		Tags: []string{"a"},
+	}, cfg)
+
+	a.Equal(Provenance{
+		"port":             "env",
+		"host":             "file",
+		"debug":            "flags",
+		"started":          "env",
+		"db.url":           "defaults",
+		"db.max_conn":      "file",
+		"cache.url":        "file",
+		"cache.max_conn":   "env",
+		"replica":          "defaults",
+		"replica.max_conn": "file",
+		"tags":             "file",
+	}, prov)
+
+	a.Equal(New(layerDatabase{URL: New("postgres://replica")}), defaults.Replica, "sources are not modified")
+	a.Equal(&layerDatabase{URL: New("redis://cache")}, file.Cache, "sources are not modified")
+}
+
+func TestLayerReplacesNested(t *testing.T) {
+	a := assert.New(t)
+
+	var cfg struct {
+		Replica Optional[layerDatabase]
+		Nested  Optional[map[string]int]
+	}
+	prov := Layer(&cfg)
+	a.Empty(prov)
+
+	type config = struct {
+		Replica Optional[layerDatabase]
+		Nested  Optional[map[string]int]
+	}
+	cfg = config{Replica: New(layerDatabase{URL: New("a")})}
+	prov = Layer(&cfg,
+		NewSource("first", config{Replica: New(layerDatabase{URL: New("b")})}),
+		NewSource("second", config{Nested: New(map[string]int{"x": 1})}),
+	)
+	a.Equal(New("b"), cfg.Replica.OrZero().URL)
+	a.Equal(Provenance{"Replica.url": "first", "Nested": "second"}, prov)
+	a.Equal("Nested: second\nReplica.url: first\n", prov.String())
+
+	a.Panics(func() {
+		s := "not a struct"
+		Layer(&s)
+	})
+}
@@ -0,0 +1,136 @@
+package opt
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+// Lenient is an optional which never fails to decode. If the input can't be
+// decoded into `T`, such as `"age": "unknown"` for an int, the optional is left
+// empty and the error is kept so it can be reported later with Err or
+// LenientErrors. This makes it possible to ingest partial records from messy
+// third-party data instead of rejecting the whole document.
+type Lenient[T any] struct {
+	value Optional[T]
+	err   error
+}
+
+// NewLenient wraps the input value in a lenient optional.
+func NewLenient[T any](value T) Lenient[T] {
+	return Lenient[T]{value: New(value)}
+}
+
+// Optional returns the decoded value as a plain optional.
+func (l Lenient[T]) Optional() Optional[T] { return l.value }
+
+// Get returns the wrapped value if it's present, `ok` signals existence.
+func (l Lenient[T]) Get() (value T, ok bool) { return l.value.Get() }
+
+// Err returns the error which occurred while decoding, if any.
+func (l Lenient[T]) Err() error { return l.err }
+
+// IsZero returns true if there's no value inside, see Optional.IsZero.
+func (l Lenient[T]) IsZero() bool { return l.value == nil }
+
+// MarshalJSON marshals the wrapped value, see Optional.MarshalJSON.
+func (l Lenient[T]) MarshalJSON() ([]byte, error) { return l.value.MarshalJSON() }
+
+// MarshalText marshals the wrapped value, see Optional.MarshalText.
+func (l Lenient[T]) MarshalText() ([]byte, error) { return l.value.MarshalText() }
+
+// UnmarshalJSON unmarshals like Optional.UnmarshalJSON except that a failure
+// produces an empty optional and is recorded instead of being returned.
+func (l *Lenient[T]) UnmarshalJSON(data []byte) error {
+	l.value, l.err = nil, nil
+	if err := l.value.UnmarshalJSON(data); err != nil {
+		l.value, l.err = nil, err
+	}
+	return nil
+}
+
+// UnmarshalText unmarshals like Optional.UnmarshalText except that a failure
+// produces an empty optional and is recorded instead of being returned.
+func (l *Lenient[T]) UnmarshalText(text []byte) error {
+	l.value, l.err = nil, nil
+	if err := l.value.UnmarshalText(text); err != nil {
+		l.value, l.err = nil, err
+	}
+	return nil
+}
+
+func (l Lenient[T]) lenientErr() error { return l.err }
+
+// LenientError is a decoding error recorded by a Lenient field.
+type LenientError struct {
+	// Field is the path to the field, using JSON names, such as "items.0.age".
+	Field string
+	Err   error
+}
+
+func (e *LenientError) Error() string { return fmt.Sprintf("%s: %v", e.Field, e.Err) }
+
+func (e *LenientError) Unwrap() error { return e.Err }
+
+// LenientErrors walks `v` and collects the errors recorded by every Lenient
+// value inside it. Structs, pointers, slices, arrays, maps and optionals are
+// walked recursively and field names follow the same rules as encoding/json.
+func LenientErrors(v any) []*LenientError {
+	var errs []*LenientError
+	collectLenientErrors(reflect.ValueOf(v), "", &errs)
+	return errs
+}
+
+type lenient interface{ lenientErr() error }
+
+func collectLenientErrors(v reflect.Value, path string, errs *[]*LenientError) {
+	if !v.IsValid() {
+		return
+	}
+
+	if v.CanInterface() {
+		if l, ok := v.Interface().(lenient); ok {
+			if err := l.lenientErr(); err != nil {
+				*errs = append(*errs, &LenientError{Field: path, Err: err})
+			}
+			return
+		}
+	}
+
+	join := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+
+	switch {
+	case optreflect.IsOptional(v.Type()):
+		if inner, ok := optreflect.Get(v); ok {
+			collectLenientErrors(inner, path, errs)
+		}
+
+	case v.Kind() == reflect.Pointer, v.Kind() == reflect.Interface:
+		if !v.IsNil() {
+			collectLenientErrors(v.Elem(), path, errs)
+		}
+
+	case v.Kind() == reflect.Struct:
+		for _, f := range optreflect.Fields(v.Type(), "json") {
+			collectLenientErrors(v.FieldByIndex(f.Index), join(f.Name), errs)
+		}
+
+	case v.Kind() == reflect.Slice, v.Kind() == reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectLenientErrors(v.Index(i), join(strconv.Itoa(i)), errs)
+		}
+
+	case v.Kind() == reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			collectLenientErrors(iter.Value(), join(fmt.Sprint(iter.Key().Interface())), errs)
+		}
+	}
+}
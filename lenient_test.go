@@ -0,0 +1,79 @@
+package opt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLenient(t *testing.T) {
+	a := assert.New(t)
+
+	type Person struct {
+		Name Lenient[string] `json:"name"`
+		Age  Lenient[int]    `json:"age"`
+	}
+	type Import struct {
+		People []Person                  `json:"people"`
+		Owner  Optional[Person]          `json:"owner"`
+		ByID   map[string]*Person        `json:"by_id"`
+		Score  Lenient[float64]          `json:"score"`
+		Extra  Optional[Lenient[string]] `json:"extra"`
+	}
+
+	var in Import
+	err := json.Unmarshal([]byte(`{
+		"people": [
+			{"name": "Southclaws", "age": 69},
+			{"name": "Someone", "age": "unknown"}
+		],
+		"owner": {"name": 5, "age": null},
+		"by_id": {"x": {"age": true}},
+		"score": 1.5
+	}`), &in)
+	a.NoError(err)
+
+	a.Equal(New("Southclaws"), in.People[0].Name.Optional())
+	a.Equal(New(69), in.People[0].Age.Optional())
+	a.NoError(in.People[0].Age.Err())
+
+	a.Equal(New("Someone"), in.People[1].Name.Optional())
+	a.Empty(in.People[1].Age.Optional())
+	a.Error(in.People[1].Age.Err())
+
+	score, ok := in.Score.Get()
+	a.True(ok)
+	a.Equal(1.5, score)
+
+	errs := LenientErrors(&in)
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+		a.Error(e.Unwrap())
+	}
+	a.Equal([]string{"people.1.age", "owner.name", "by_id.x.age"}, fields)
+	a.Contains(errs[0].Error(), "people.1.age: ")
+
+	b, err := json.Marshal(in.People)
+	a.NoError(err)
+	a.Equal(`[{"name":"Southclaws","age":69},{"name":"Someone","age":null}]`, string(b))
+}
+
+func TestLenientText(t *testing.T) {
+	a := assert.New(t)
+
+	l := NewLenient(5)
+	a.NoError(l.UnmarshalText([]byte("five")))
+	a.Empty(l.Optional())
+	a.True(l.IsZero())
+	a.Error(l.Err())
+
+	a.NoError(l.UnmarshalText([]byte("6")))
+	a.Equal(New(6), l.Optional())
+	a.NoError(l.Err())
+
+	text, err := l.MarshalText()
+	a.NoError(err)
+	a.Equal("6", string(text))
+}
@@ -0,0 +1,145 @@
+package opt
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type valueMarshaler struct{ V string }
+
+func (m valueMarshaler) MarshalJSON() ([]byte, error)   { return json.Marshal("json:" + m.V) }
+func (m valueMarshaler) MarshalText() ([]byte, error)   { return []byte("text:" + m.V), nil }
+func (m valueMarshaler) MarshalBinary() ([]byte, error) { return []byte("binary:" + m.V), nil }
+
+type pointerMarshaler struct{ V string }
+
+func (m *pointerMarshaler) MarshalJSON() ([]byte, error)   { return json.Marshal("json:" + m.V) }
+func (m *pointerMarshaler) MarshalText() ([]byte, error)   { return []byte("text:" + m.V), nil }
+func (m *pointerMarshaler) MarshalBinary() ([]byte, error) { return []byte("binary:" + m.V), nil }
+func (m *pointerMarshaler) GobEncode() ([]byte, error)     { return []byte("gob:" + m.V), nil }
+func (m *pointerMarshaler) GobDecode(b []byte) error {
+	m.V = string(bytes.TrimPrefix(b, []byte("gob:")))
+	return nil
+}
+
+type embeddedMarshaler struct {
+	pointerMarshaler
+}
+
+type marshalers struct {
+	Value          valueMarshaler                     `json:"value" xml:"value"`
+	OptValue       Optional[valueMarshaler]           `json:"opt_value" xml:"opt_value"`
+	Pointer        pointerMarshaler                   `json:"pointer" xml:"pointer"`
+	OptPointer     Optional[pointerMarshaler]         `json:"opt_pointer" xml:"opt_pointer"`
+	Embedded       embeddedMarshaler                  `json:"embedded" xml:"embedded"`
+	OptEmbedded    Optional[embeddedMarshaler]        `json:"opt_embedded" xml:"opt_embedded"`
+	AttrPointer    pointerMarshaler                   `json:"-" xml:"attr_pointer,attr"`
+	OptAttrPointer Optional[pointerMarshaler]         `json:"-" xml:"opt_attr_pointer,attr"`
+	Nullable       Nullable[pointerMarshaler]         `json:"nullable" xml:"-"`
+	Nested         Optional[Optional[valueMarshaler]] `json:"nested" xml:"-"`
+}
+
+func newMarshalers() *marshalers {
+	return &marshalers{
+		Value:          valueMarshaler{"a"},
+		OptValue:       New(valueMarshaler{"a"}),
+		Pointer:        pointerMarshaler{"b"},
+		OptPointer:     New(pointerMarshaler{"b"}),
+		Embedded:       embeddedMarshaler{pointerMarshaler{"c"}},
+		OptEmbedded:    New(embeddedMarshaler{pointerMarshaler{"c"}}),
+		AttrPointer:    pointerMarshaler{"d"},
+		OptAttrPointer: New(pointerMarshaler{"d"}),
+		Nullable:       NewNullable(pointerMarshaler{"e"}),
+		Nested:         New(New(valueMarshaler{"f"})),
+	}
+}
+
+func TestMarshalersJSON(t *testing.T) {
+	a := assert.New(t)
+
+	b, err := json.Marshal(newMarshalers())
+	a.NoError(err)
+	a.JSONEq(`{
+		"value": "json:a",
+		"opt_value": "json:a",
+		"pointer": "json:b",
+		"opt_pointer": "json:b",
+		"embedded": "json:c",
+		"opt_embedded": "json:c",
+		"nullable": "json:e",
+		"nested": "json:f"
+	}`, string(b))
+}
+
+func TestMarshalersXML(t *testing.T) {
+	a := assert.New(t)
+
+	b, err := xml.Marshal(newMarshalers())
+	a.NoError(err)
+	a.Equal(`<marshalers attr_pointer="text:d" opt_attr_pointer="text:d">`+
+		`<value>text:a</value><opt_value>text:a</opt_value>`+
+		`<pointer>text:b</pointer><opt_pointer>text:b</opt_pointer>`+
+		`<embedded>text:c</embedded><opt_embedded>text:c</opt_embedded>`+
+		`</marshalers>`, string(b))
+}
+
+func TestMarshalersText(t *testing.T) {
+	a := assert.New(t)
+
+	m := newMarshalers()
+	for _, test := range []struct {
+		Plain    func() ([]byte, error)
+		Optional func() ([]byte, error)
+	}{
+		{m.Value.MarshalText, m.OptValue.MarshalText},
+		{m.Pointer.MarshalText, m.OptPointer.MarshalText},
+		{m.Embedded.MarshalText, m.OptEmbedded.MarshalText},
+	} {
+		plain, err := test.Plain()
+		a.NoError(err)
+		optional, err := test.Optional()
+		a.NoError(err)
+		a.Equal(string(plain), string(optional))
+	}
+}
+
+func TestMarshalersBinary(t *testing.T) {
+	a := assert.New(t)
+
+	m := newMarshalers()
+	for _, test := range []struct {
+		Plain    func() ([]byte, error)
+		Optional func() ([]byte, error)
+	}{
+		{m.Value.MarshalBinary, m.OptValue.MarshalBinary},
+		{m.Pointer.MarshalBinary, m.OptPointer.MarshalBinary},
+		{m.Embedded.MarshalBinary, m.OptEmbedded.MarshalBinary},
+	} {
+		plain, err := test.Plain()
+		a.NoError(err)
+		optional, err := test.Optional()
+		a.NoError(err)
+		a.Equal(append([]byte{binaryPresent}, plain...), optional)
+	}
+}
+
+func TestMarshalersGob(t *testing.T) {
+	a := assert.New(t)
+
+	in := New(pointerMarshaler{"g"})
+	b, err := in.GobEncode()
+	a.NoError(err)
+
+	var buf bytes.Buffer
+	a.NoError(gob.NewEncoder(&buf).Encode(&pointerMarshaler{"g"}))
+	a.Equal(append([]byte{1}, buf.Bytes()...), b)
+
+	var out Optional[pointerMarshaler]
+	a.NoError(out.GobDecode(b))
+	a.Equal(in, out)
+}
@@ -0,0 +1,180 @@
+package opt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+// Merge applies an RFC 7396 JSON Merge Patch document to `dst`, which is
+// usually a struct containing optional fields.
+//
+// A `null` member clears the field, which makes an optional empty, makes a
+// Nullable explicitly null and sets any other type to its zero value. Any other
+// value is unmarshaled into the field, so an optional becomes present. Objects
+// are merged recursively into structs, optional and nullable structs, pointers
+// to structs and maps with string keys. Members which don't match a field are
+// ignored. Field names follow the same rules as encoding/json, including `json`
+// struct tags.
+func Merge[T any, P ~[]byte | ~string](dst *T, patch P) error {
+	if dst == nil {
+		return fmt.Errorf("opt: merge destination is nil")
+	}
+	return merge(reflect.ValueOf(dst).Elem(), []byte(patch))
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+func merge(v reflect.Value, patch []byte) error {
+	patch = bytes.TrimSpace(patch)
+
+	if bytes.Equal(patch, []byte("null")) {
+		// The zero value of a Nullable means unset rather than null, so it's
+		// given an explicit null instead.
+		if n, ok := v.Addr().Interface().(nuller); ok {
+			n.setNull()
+			return nil
+		}
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	// Anything that isn't an object replaces the target entirely, as do types
+	// which handle their own JSON such as time.Time.
+	if len(patch) == 0 || patch[0] != '{' || (!optreflect.IsOptional(v.Type()) && !optreflect.IsNullable(v.Type()) && reflect.PointerTo(v.Type()).Implements(unmarshalerType)) {
+		return json.Unmarshal(patch, v.Addr().Interface())
+	}
+
+	switch {
+	case optreflect.IsOptional(v.Type()):
+		inner := reflect.New(v.Type().Elem()).Elem()
+		if current, ok := optreflect.Get(v); ok {
+			inner.Set(current)
+		}
+		if err := merge(inner, patch); err != nil {
+			return err
+		}
+		optreflect.Set(v, inner)
+		return nil
+
+	case optreflect.IsNullable(v.Type()):
+		// A Nullable which is set wraps an optional, which is merged as above.
+		inner := reflect.New(v.Type().Elem()).Elem()
+		if current, ok := optreflect.Get(v); ok {
+			inner.Set(current)
+		}
+		if err := merge(inner, patch); err != nil {
+			return err
+		}
+		optreflect.Set(v, inner)
+		return nil
+
+	case v.Kind() == reflect.Pointer:
+		inner := reflect.New(v.Type().Elem())
+		if !v.IsNil() {
+			inner.Elem().Set(v.Elem())
+		}
+		if err := merge(inner.Elem(), patch); err != nil {
+			return err
+		}
+		v.Set(inner)
+		return nil
+
+	case v.Kind() == reflect.Interface && v.NumMethod() == 0:
+		m := reflect.ValueOf(map[string]any{})
+		if current, ok := v.Interface().(map[string]any); ok {
+			for k, e := range current {
+				m.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(e))
+			}
+		}
+		if err := mergeMap(m, patch); err != nil {
+			return err
+		}
+		v.Set(m)
+		return nil
+
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		m := reflect.MakeMap(v.Type())
+		iter := v.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(iter.Key(), iter.Value())
+		}
+		if err := mergeMap(m, patch); err != nil {
+			return err
+		}
+		v.Set(m)
+		return nil
+
+	case v.Kind() == reflect.Struct:
+		return mergeStruct(v, patch)
+	}
+
+	return json.Unmarshal(patch, v.Addr().Interface())
+}
+
+func mergeStruct(v reflect.Value, patch []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &members); err != nil {
+		return err
+	}
+
+	fields := optreflect.Fields(v.Type(), "json")
+	for name, raw := range members {
+		f, ok := findField(fields, name)
+		if !ok {
+			continue
+		}
+		if err := merge(v.FieldByIndex(f.Index), raw); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func mergeMap(m reflect.Value, patch []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &members); err != nil {
+		return err
+	}
+
+	for name, raw := range members {
+		key := reflect.ValueOf(name).Convert(m.Type().Key())
+
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			m.SetMapIndex(key, reflect.Value{})
+			continue
+		}
+
+		elem := reflect.New(m.Type().Elem()).Elem()
+		if current := m.MapIndex(key); current.IsValid() {
+			elem.Set(current)
+		}
+		if err := merge(elem, raw); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		m.SetMapIndex(key, elem)
+	}
+
+	return nil
+}
+
+// findField matches a member name to a field, preferring an exact match over a
+// case-insensitive one in the same way as encoding/json.
+func findField(fields []optreflect.Field, name string) (optreflect.Field, bool) {
+	for _, f := range fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return optreflect.Field{}, false
+}
@@ -0,0 +1,140 @@
+package opt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mergeAddress struct {
+	Line1 Optional[string] `json:"line1"`
+	City  Optional[string] `json:"city"`
+}
+
+type mergeAccount struct {
+	ID       string                 `json:"id"`
+	Name     Optional[string]       `json:"name"`
+	Age      Optional[int]          `json:"age"`
+	Address  mergeAddress           `json:"address"`
+	Billing  Optional[mergeAddress] `json:"billing"`
+	Shipping *mergeAddress          `json:"shipping"`
+	Tags     []string               `json:"tags"`
+	Meta     map[string]string      `json:"meta"`
+	Extra    map[string]any         `json:"extra"`
+	Created  Optional[time.Time]    `json:"created"`
+}
+
+func TestMerge(t *testing.T) {
+	a := assert.New(t)
+
+	dst := mergeAccount{
+		ID:      "southclaws",
+		Name:    New("Southclaws"),
+		Age:     New(69),
+		Address: mergeAddress{Line1: New("1 Street"), City: New("London")},
+		Tags:    []string{"a", "b"},
+		Meta:    map[string]string{"keep": "yes", "drop": "yes"},
+		Extra:   map[string]any{"nested": map[string]any{"a": "b", "c": "d"}},
+	}
+
+	err := Merge(&dst, `{
+		"name": null,
+		"age": 70,
+		"unknown": "ignored",
+		"address": {"city": "Bristol"},
+		"billing": {"city": "Leeds"},
+		"shipping": {"line1": "2 Road"},
+		"tags": ["c"],
+		"meta": {"drop": null, "add": "yes"},
+		"extra": {"nested": {"a": null, "e": "f"}},
+		"created": "2006-01-02T15:04:05Z"
+	}`)
+	a.NoError(err)
+
+	a.Equal("southclaws", dst.ID)
+	a.Empty(dst.Name)
+	a.Equal(New(70), dst.Age)
+	a.Equal(mergeAddress{Line1: New("1 Street"), City: New("Bristol")}, dst.Address)
+	a.Equal(New(mergeAddress{City: New("Leeds")}), dst.Billing)
+	a.Equal(&mergeAddress{Line1: New("2 Road")}, dst.Shipping)
+	a.Equal([]string{"c"}, dst.Tags)
+	a.Equal(map[string]string{"keep": "yes", "add": "yes"}, dst.Meta)
+	a.Equal(map[string]any{"nested": map[string]any{"c": "d", "e": "f"}}, dst.Extra)
+	a.Equal(New(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)), dst.Created)
+
+	err = Merge(&dst, []byte(`{"billing": {"line1": "3 Lane"}, "shipping": null, "address": null}`))
+	a.NoError(err)
+	a.Equal(New(mergeAddress{Line1: New("3 Lane"), City: New("Leeds")}), dst.Billing)
+	a.Nil(dst.Shipping)
+	a.Equal(mergeAddress{}, dst.Address)
+}
+
+func TestMergeNullable(t *testing.T) {
+	a := assert.New(t)
+
+	var dst struct {
+		Nickname Nullable[string] `json:"nickname"`
+		Email    Nullable[string] `json:"email"`
+		Phone    Nullable[string] `json:"phone"`
+	}
+	dst.Email = NewNullable("a@example.com")
+	dst.Phone = NewNullable("0123")
+
+	err := Merge(&dst, `{"nickname": "southclaws", "email": null}`)
+	a.NoError(err)
+
+	a.Equal(NewNullable("southclaws"), dst.Nickname)
+	a.True(dst.Email.IsSet(), "null is an explicit null, not unset")
+	a.True(dst.Email.IsNull())
+	a.Equal(NewNullable("0123"), dst.Phone)
+}
+
+func TestMergeNullableStruct(t *testing.T) {
+	a := assert.New(t)
+
+	var dst struct {
+		Set   Nullable[mergeAddress] `json:"set"`
+		Null  Nullable[mergeAddress] `json:"null"`
+		Unset Nullable[mergeAddress] `json:"unset"`
+	}
+	dst.Set = NewNullable(mergeAddress{Line1: New("1 Street"), City: New("London")})
+	dst.Null = NewNull[mergeAddress]()
+
+	err := Merge(&dst, `{"set": {"city": "Leeds"}, "null": {"city": "York"}, "unset": {"line1": "2 Road"}}`)
+	a.NoError(err)
+
+	a.Equal(NewNullable(mergeAddress{Line1: New("1 Street"), City: New("Leeds")}), dst.Set, "sibling fields are kept")
+	a.Equal(NewNullable(mergeAddress{City: New("York")}), dst.Null)
+	a.Equal(NewNullable(mergeAddress{Line1: New("2 Road")}), dst.Unset)
+}
+
+func TestMergeDoesNotAlias(t *testing.T) {
+	a := assert.New(t)
+
+	original := mergeAccount{
+		Billing:  New(mergeAddress{City: New("Leeds")}),
+		Shipping: &mergeAddress{City: New("Leeds")},
+		Meta:     map[string]string{"a": "b"},
+	}
+	dst := original
+
+	err := Merge(&dst, `{"billing": {"city": "York"}, "shipping": {"city": "York"}, "meta": {"a": "c"}}`)
+	a.NoError(err)
+
+	a.Equal("York", dst.Billing.OrZero().City.OrZero())
+	a.Equal("Leeds", original.Billing.OrZero().City.OrZero())
+	a.Equal("Leeds", original.Shipping.City.OrZero())
+	a.Equal("b", original.Meta["a"])
+}
+
+func TestMergeErrors(t *testing.T) {
+	a := assert.New(t)
+
+	var dst mergeAccount
+	a.Error(Merge(&dst, `{"age": "old"}`))
+	a.Error(Merge(&dst, `{"address": {"city": 5}}`))
+	a.Error(Merge(&dst, `[]`))
+	a.Error(Merge(&dst, `{`))
+	a.Error(Merge[mergeAccount](nil, `{}`))
+}
@@ -0,0 +1,97 @@
+package opt
+
+import (
+	"fmt"
+)
+
+// Nullable is a tri-state optional which distinguishes a value that was never
+// set, a value that was explicitly set to null and a value that is present.
+// This is mostly useful for JSON PATCH style APIs where an omitted field means
+// "leave it alone" and a `null` field means "clear it".
+//
+// The zero value is unset. Since Nullable is a slice, `omitempty` omits an
+// unset value while a null value is still encoded as `null`.
+type Nullable[T any] container[Optional[T]]
+
+// NewNullable creates a nullable which is set to `value`.
+func NewNullable[T any](value T) Nullable[T] { return Nullable[T]{New(value)} }
+
+// NewNull creates a nullable which is explicitly set to null.
+func NewNull[T any]() Nullable[T] { return Nullable[T]{NewEmpty[T]()} }
+
+// NewUnset creates a nullable which has not been set.
+func NewUnset[T any]() Nullable[T] { return nil }
+
+// NewNullableFrom converts an optional into a nullable. A present optional is
+// set to its value and an empty optional is explicitly null.
+func NewNullableFrom[T any](o Optional[T]) Nullable[T] { return Nullable[T]{o} }
+
+// IsSet returns true if the nullable was set, either to a value or to null.
+func (n Nullable[T]) IsSet() bool {
+	return n != nil
+}
+
+// IsNull returns true if the nullable was explicitly set to null.
+func (n Nullable[T]) IsNull() bool {
+	return n != nil && n[0] == nil
+}
+
+// Get returns the wrapped value if it's present, `ok` signals existence.
+func (n Nullable[T]) Get() (value T, ok bool) {
+	if n == nil {
+		return
+	}
+	return n[0].Get()
+}
+
+// Optional converts the nullable into an optional which is only present if
+// the nullable was set to a value. Unset and null both produce empty.
+func (n Nullable[T]) Optional() Optional[T] {
+	if n == nil {
+		return nil
+	}
+	return n[0]
+}
+
+// String returns the string representation of the value or an empty string.
+func (n Nullable[T]) String() string {
+	return n.Optional().String()
+}
+
+// GoString is only used for verbose printing.
+func (n Nullable[T]) GoString() string {
+	if n == nil {
+		return "Nullable[unset]"
+	}
+	if v, ok := n[0].Get(); ok {
+		return fmt.Sprintf("Nullable[%v]", v)
+	}
+	return "Nullable[null]"
+}
+
+// MarshalJSON marshals the value being wrapped to JSON. Both unset and null
+// are marshaled as `null`, use `omitempty` to leave unset values out entirely.
+func (n Nullable[T]) MarshalJSON() (data []byte, err error) {
+	return n.Optional().MarshalJSON()
+}
+
+// UnmarshalJSON unmarshals the JSON into this nullable. This is only called
+// for keys which are present so a `null` produces an explicit null and any
+// other value is set. Keys which are missing leave the nullable unset. See
+// Optional.UnmarshalJSON for how the outer decoder's settings apply.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	var o Optional[T]
+	err := o.UnmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+
+	*n = Nullable[T]{o}
+	return nil
+}
+
+// nuller is implemented by Nullable so that Merge can set an explicit null
+// without knowing the type parameter.
+type nuller interface{ setNull() }
+
+func (n *Nullable[T]) setNull() { *n = NewNull[T]() }
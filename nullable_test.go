@@ -0,0 +1,68 @@
+package opt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullable(t *testing.T) {
+	a := assert.New(t)
+
+	tests := []struct {
+		Nullable   Nullable[string]
+		IsSet      bool
+		IsNull     bool
+		Value      string
+		Ok         bool
+		GoString   string
+		IsOptional bool
+	}{
+		{NewUnset[string](), false, false, "", false, "Nullable[unset]", false},
+		{NewNull[string](), true, true, "", false, "Nullable[null]", false},
+		{NewNullable(""), true, false, "", true, "Nullable[]", true},
+		{NewNullable("value"), true, false, "value", true, "Nullable[value]", true},
+		{NewNullableFrom(NewEmpty[string]()), true, true, "", false, "Nullable[null]", false},
+		{NewNullableFrom(New("value")), true, false, "value", true, "Nullable[value]", true},
+	}
+
+	for _, test := range tests {
+		a.Equal(test.IsSet, test.Nullable.IsSet(), test.GoString)
+		a.Equal(test.IsNull, test.Nullable.IsNull(), test.GoString)
+
+		v, ok := test.Nullable.Get()
+		a.Equal(test.Value, v, test.GoString)
+		a.Equal(test.Ok, ok, test.GoString)
+		a.Equal(test.GoString, test.Nullable.GoString())
+		a.Equal(test.IsOptional, test.Nullable.Optional().Ok(), test.GoString)
+		a.Equal(test.Value, test.Nullable.String(), test.GoString)
+	}
+}
+
+func TestNullableJSON(t *testing.T) {
+	a := assert.New(t)
+
+	type Patch struct {
+		Name  Nullable[string] `json:"name,omitempty"`
+		Email Nullable[string] `json:"email,omitempty"`
+		Age   Nullable[int]    `json:"age,omitempty"`
+		Bio   Nullable[string] `json:"bio"`
+	}
+
+	var p Patch
+	err := json.Unmarshal([]byte(`{"name":"Southclaws","email":null}`), &p)
+	a.NoError(err)
+
+	a.Equal(NewNullable("Southclaws"), p.Name)
+	a.Equal(NewNull[string](), p.Email)
+	a.Equal(NewUnset[int](), p.Age)
+	a.Equal(NewUnset[string](), p.Bio)
+
+	b, err := json.Marshal(p)
+	a.NoError(err)
+	a.Equal(`{"name":"Southclaws","email":null,"bio":null}`, string(b))
+
+	err = json.Unmarshal([]byte(`{"age":"old"}`), &p)
+	a.Error(err)
+}
@@ -0,0 +1,23 @@
+//go:build go1.24
+
+package opt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONOmitZero(t *testing.T) {
+	a := assert.New(t)
+
+	type Data struct {
+		Name Optional[string] `json:"name,omitzero"`
+		Age  Optional[int]    `json:"age,omitzero"`
+	}
+
+	b, err := json.Marshal(Data{Age: New(0)})
+	a.NoError(err)
+	a.Equal(`{"age":0}`, string(b))
+}
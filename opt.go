@@ -252,16 +252,33 @@ func (o Optional[T]) GoString() string {
 	return "Optional[]"
 }
 
+// IsZero returns true if there's no value inside. This is used by the
+// `omitzero` option of encoding/json to omit empty optionals.
+func (o Optional[T]) IsZero() bool {
+	return o == nil
+}
+
 // MarshalJSON marshals the value being wrapped to JSON. If there is no vale
 // being wrapped, the zero value of its type is marshaled.
+//
+// The value is marshaled through a pointer so that marshalers which `T` defines
+// on a pointer receiver are used, just as they are for an addressable `T`.
 func (o Optional[T]) MarshalJSON() (data []byte, err error) {
-	if v, ok := o.Get(); ok {
-		return json.Marshal(v)
+	if o != nil {
+		return json.Marshal(&o[0])
 	}
 	return []byte("null"), nil
 }
 
 // UnmarshalJSON unmarshals the JSON into a value wrapped by this optional.
+//
+// When encoding/json is backed by encoding/json/v2 (GOEXPERIMENT=jsonv2, which
+// recent toolchains enable by default) it calls UnmarshalJSONFrom instead, so
+// settings on the outer Decoder such as UseNumber and DisallowUnknownFields
+// also apply to the wrapped value. The classic implementation gives this
+// method no access to those settings. In both cases errors are returned as-is
+// so that an UnmarshalTypeError reports the full field path and the expected
+// type.
 func (o *Optional[T]) UnmarshalJSON(data []byte) error {
 	if string(data) == "null" {
 		*o = NewEmpty[T]()
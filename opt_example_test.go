@@ -2,6 +2,7 @@ package opt_test
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"strings"
 	"time"
@@ -468,3 +469,295 @@ func Example_jsonUnmarshalPresent() {
 	// Uint: true 0
 	// Uintptr: true 0
 }
+
+func Example_textUnmarshal() {
+	var (
+		name opt.Optional[string]
+		age  opt.Optional[int]
+	)
+
+	_, err := fmt.Sscan("Southclaws 69", &name, &age)
+	fmt.Println("error:", err)
+	fmt.Println("Name:", name.Ok(), name)
+	fmt.Println("Age:", age.Ok(), age)
+
+	err = age.UnmarshalText([]byte(""))
+	fmt.Println("error:", err)
+	fmt.Println("Age:", age.Ok(), age)
+
+	// Output:
+	// error: <nil>
+	// Name: true Southclaws
+	// Age: true 69
+	// error: <nil>
+	// Age: false
+}
+
+func Example_xmlMarshalEmpty() {
+	s := struct {
+		XMLName xml.Name                `xml:"data"`
+		Bool    opt.Optional[bool]      `xml:"bool"`
+		Byte    opt.Optional[byte]      `xml:"byte"`
+		Float32 opt.Optional[float32]   `xml:"float32"`
+		Float64 opt.Optional[float64]   `xml:"float64"`
+		Int16   opt.Optional[int16]     `xml:"int16"`
+		Int32   opt.Optional[int32]     `xml:"int32"`
+		Int64   opt.Optional[int64]     `xml:"int64"`
+		Int     opt.Optional[int]       `xml:"int"`
+		Rune    opt.Optional[rune]      `xml:"rune"`
+		String  opt.Optional[string]    `xml:"string"`
+		Time    opt.Optional[time.Time] `xml:"time"`
+		Uint16  opt.Optional[uint16]    `xml:"uint16"`
+		Uint32  opt.Optional[uint32]    `xml:"uint32"`
+		Uint64  opt.Optional[uint64]    `xml:"uint64"`
+		Uint    opt.Optional[uint]      `xml:"uint"`
+		Uintptr opt.Optional[uintptr]   `xml:"uintptr"`
+	}{
+		Bool:    opt.NewEmpty[bool](),
+		Byte:    opt.NewEmpty[byte](),
+		Float32: opt.NewEmpty[float32](),
+		Float64: opt.NewEmpty[float64](),
+		Int16:   opt.NewEmpty[int16](),
+		Int32:   opt.NewEmpty[int32](),
+		Int64:   opt.NewEmpty[int64](),
+		Int:     opt.NewEmpty[int](),
+		Rune:    opt.NewEmpty[rune](),
+		String:  opt.NewEmpty[string](),
+		Time:    opt.NewEmpty[time.Time](),
+		Uint16:  opt.NewEmpty[uint16](),
+		Uint32:  opt.NewEmpty[uint32](),
+		Uint64:  opt.NewEmpty[uint64](),
+		Uint:    opt.NewEmpty[uint](),
+		Uintptr: opt.NewEmpty[uintptr](),
+	}
+
+	output, _ := xml.MarshalIndent(s, "", "  ")
+	fmt.Println(string(output))
+
+	// Output:
+	// <data></data>
+}
+
+func Example_xmlMarshalPresent() {
+	s := struct {
+		XMLName xml.Name                `xml:"data"`
+		Bool    opt.Optional[bool]      `xml:"bool"`
+		Byte    opt.Optional[byte]      `xml:"byte"`
+		Float32 opt.Optional[float32]   `xml:"float32"`
+		Float64 opt.Optional[float64]   `xml:"float64"`
+		Int16   opt.Optional[int16]     `xml:"int16"`
+		Int32   opt.Optional[int32]     `xml:"int32"`
+		Int64   opt.Optional[int64]     `xml:"int64"`
+		Int     opt.Optional[int]       `xml:"int"`
+		Rune    opt.Optional[rune]      `xml:"rune"`
+		String  opt.Optional[string]    `xml:"string"`
+		Time    opt.Optional[time.Time] `xml:"time"`
+		Uint16  opt.Optional[uint16]    `xml:"uint16"`
+		Uint32  opt.Optional[uint32]    `xml:"uint32"`
+		Uint64  opt.Optional[uint64]    `xml:"uint64"`
+		Uint    opt.Optional[uint]      `xml:"uint"`
+		Uintptr opt.Optional[uintptr]   `xml:"uintptr"`
+	}{
+		Bool:    opt.New(true),
+		Byte:    opt.New[byte](1),
+		Float32: opt.New[float32](2.1),
+		Float64: opt.New(2.2),
+		Int16:   opt.New[int16](3),
+		Int32:   opt.New[int32](4),
+		Int64:   opt.New[int64](5),
+		Int:     opt.New(6),
+		Rune:    opt.New[rune](7),
+		String:  opt.New("string"),
+		Time:    opt.New(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)),
+		Uint16:  opt.New[uint16](8),
+		Uint32:  opt.New[uint32](9),
+		Uint64:  opt.New[uint64](10),
+		Uint:    opt.New[uint](11),
+		Uintptr: opt.New[uintptr](12),
+	}
+
+	output, _ := xml.MarshalIndent(s, "", "  ")
+	fmt.Println(string(output))
+
+	// Output:
+	// <data>
+	//   <bool>true</bool>
+	//   <byte>1</byte>
+	//   <float32>2.1</float32>
+	//   <float64>2.2</float64>
+	//   <int16>3</int16>
+	//   <int32>4</int32>
+	//   <int64>5</int64>
+	//   <int>6</int>
+	//   <rune>7</rune>
+	//   <string>string</string>
+	//   <time>2006-01-02T15:04:05Z</time>
+	//   <uint16>8</uint16>
+	//   <uint32>9</uint32>
+	//   <uint64>10</uint64>
+	//   <uint>11</uint>
+	//   <uintptr>12</uintptr>
+	// </data>
+}
+
+func Example_xmlUnmarshalEmpty() {
+	s := struct {
+		XMLName xml.Name                `xml:"data"`
+		Bool    opt.Optional[bool]      `xml:"bool"`
+		Byte    opt.Optional[byte]      `xml:"byte"`
+		Float32 opt.Optional[float32]   `xml:"float32"`
+		Float64 opt.Optional[float64]   `xml:"float64"`
+		Int16   opt.Optional[int16]     `xml:"int16"`
+		Int32   opt.Optional[int32]     `xml:"int32"`
+		Int64   opt.Optional[int64]     `xml:"int64"`
+		Int     opt.Optional[int]       `xml:"int"`
+		Rune    opt.Optional[rune]      `xml:"rune"`
+		String  opt.Optional[string]    `xml:"string"`
+		Time    opt.Optional[time.Time] `xml:"time"`
+		Uint16  opt.Optional[uint16]    `xml:"uint16"`
+		Uint32  opt.Optional[uint32]    `xml:"uint32"`
+		Uint64  opt.Optional[uint64]    `xml:"uint64"`
+		Uint    opt.Optional[uint]      `xml:"uint"`
+		Uintptr opt.Optional[uintptr]   `xml:"uintptr"`
+	}{}
+
+	x := `<data></data>`
+	err := xml.Unmarshal([]byte(x), &s)
+	fmt.Println("error:", err)
+	fmt.Println("Bool:", s.Bool.Ok())
+	fmt.Println("Byte:", s.Byte.Ok())
+	fmt.Println("Float32:", s.Float32.Ok())
+	fmt.Println("Float64:", s.Float64.Ok())
+	fmt.Println("Int16:", s.Int16.Ok())
+	fmt.Println("Int32:", s.Int32.Ok())
+	fmt.Println("Int64:", s.Int64.Ok())
+	fmt.Println("Int:", s.Int.Ok())
+	fmt.Println("Rune:", s.Rune.Ok())
+	fmt.Println("String:", s.String.Ok())
+	fmt.Println("Time:", s.Time.Ok())
+	fmt.Println("Uint16:", s.Uint16.Ok())
+	fmt.Println("Uint32:", s.Uint32.Ok())
+	fmt.Println("Uint64:", s.Uint64.Ok())
+	fmt.Println("Uint:", s.Uint.Ok())
+	fmt.Println("Uintptr:", s.Uintptr.Ok())
+
+	// Output:
+	// error: <nil>
+	// Bool: false
+	// Byte: false
+	// Float32: false
+	// Float64: false
+	// Int16: false
+	// Int32: false
+	// Int64: false
+	// Int: false
+	// Rune: false
+	// String: false
+	// Time: false
+	// Uint16: false
+	// Uint32: false
+	// Uint64: false
+	// Uint: false
+	// Uintptr: false
+}
+
+func Example_xmlUnmarshalPresent() {
+	s := struct {
+		XMLName xml.Name                `xml:"data"`
+		Bool    opt.Optional[bool]      `xml:"bool"`
+		Byte    opt.Optional[byte]      `xml:"byte"`
+		Float32 opt.Optional[float32]   `xml:"float32"`
+		Float64 opt.Optional[float64]   `xml:"float64"`
+		Int16   opt.Optional[int16]     `xml:"int16"`
+		Int32   opt.Optional[int32]     `xml:"int32"`
+		Int64   opt.Optional[int64]     `xml:"int64"`
+		Int     opt.Optional[int]       `xml:"int"`
+		Rune    opt.Optional[rune]      `xml:"rune"`
+		String  opt.Optional[string]    `xml:"string"`
+		Time    opt.Optional[time.Time] `xml:"time"`
+		Uint16  opt.Optional[uint16]    `xml:"uint16"`
+		Uint32  opt.Optional[uint32]    `xml:"uint32"`
+		Uint64  opt.Optional[uint64]    `xml:"uint64"`
+		Uint    opt.Optional[uint]      `xml:"uint"`
+		Uintptr opt.Optional[uintptr]   `xml:"uintptr"`
+	}{}
+
+	x := `<data>
+   <bool>false</bool>
+   <byte>0</byte>
+   <float32>0</float32>
+   <float64>0</float64>
+   <int16>0</int16>
+   <int32>0</int32>
+   <int64>0</int64>
+   <int>0</int>
+   <rune>0</rune>
+   <string>string</string>
+   <time>0001-01-01T00:00:00Z</time>
+   <uint16>0</uint16>
+   <uint32>0</uint32>
+   <uint64>0</uint64>
+   <uint>0</uint>
+   <uintptr>0</uintptr>
+ </data>`
+	err := xml.Unmarshal([]byte(x), &s)
+	fmt.Println("error:", err)
+	fmt.Println("Bool:", s.Bool.Ok(), s.Bool)
+	fmt.Println("Byte:", s.Byte.Ok(), s.Byte)
+	fmt.Println("Float32:", s.Float32.Ok(), s.Float32)
+	fmt.Println("Float64:", s.Float64.Ok(), s.Float64)
+	fmt.Println("Int16:", s.Int16.Ok(), s.Int16)
+	fmt.Println("Int32:", s.Int32.Ok(), s.Int32)
+	fmt.Println("Int64:", s.Int64.Ok(), s.Int64)
+	fmt.Println("Int:", s.Int.Ok(), s.Int)
+	fmt.Println("Rune:", s.Rune.Ok(), s.Rune)
+	fmt.Println("String:", s.String.Ok(), s.String)
+	fmt.Println("Time:", s.Time.Ok(), s.Time)
+	fmt.Println("Uint16:", s.Uint16.Ok(), s.Uint16)
+	fmt.Println("Uint32:", s.Uint32.Ok(), s.Uint32)
+	fmt.Println("Uint64:", s.Uint64.Ok(), s.Uint64)
+	fmt.Println("Uint:", s.Uint.Ok(), s.Uint)
+	fmt.Println("Uintptr:", s.Uintptr.Ok(), s.Uintptr)
+
+	// Output:
+	// error: <nil>
+	// Bool: true false
+	// Byte: true 0
+	// Float32: true 0
+	// Float64: true 0
+	// Int16: true 0
+	// Int32: true 0
+	// Int64: true 0
+	// Int: true 0
+	// Rune: true 0
+	// String: true string
+	// Time: true 0001-01-01 00:00:00 +0000 UTC
+	// Uint16: true 0
+	// Uint32: true 0
+	// Uint64: true 0
+	// Uint: true 0
+	// Uintptr: true 0
+}
+
+func Example_xmlAttr() {
+	type Data struct {
+		XMLName xml.Name             `xml:"data"`
+		Name    opt.Optional[string] `xml:"name,attr"`
+		Age     opt.Optional[int]    `xml:"age,attr"`
+	}
+
+	output, _ := xml.Marshal(Data{Name: opt.New("Southclaws")})
+	fmt.Println(string(output))
+
+	var d Data
+	err := xml.Unmarshal([]byte(`<data age="69"></data>`), &d)
+	fmt.Println("error:", err)
+	fmt.Println("Name:", d.Name.Ok())
+	fmt.Println("Age:", d.Age.Ok(), d.Age)
+
+	// Output:
+	// <data name="Southclaws"></data>
+	// error: <nil>
+	// Name: false
+	// Age: true 69
+}
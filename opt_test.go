@@ -80,6 +80,14 @@ func TestGet(t *testing.T) {
 	}
 }
 
+func TestIsZero(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(NewEmpty[string]().IsZero())
+	a.False(New("").IsZero())
+	a.False(New("value").IsZero())
+}
+
 func TestGetMap(t *testing.T) {
 	a := assert.New(t)
 
@@ -300,3 +308,27 @@ func TestJSON(t *testing.T) {
 	a.Empty(out.Name)
 	a.Empty(out.Age)
 }
+
+func TestJSONErrors(t *testing.T) {
+	a := assert.New(t)
+
+	type Inner struct {
+		N int `json:"n"`
+	}
+	type Outer struct {
+		Age   Optional[int]   `json:"age"`
+		Inner Optional[Inner] `json:"inner"`
+	}
+
+	var typeErr *json.UnmarshalTypeError
+
+	err := json.Unmarshal([]byte(`{"age":"old"}`), &Outer{})
+	a.ErrorAs(err, &typeErr)
+	a.Equal("age", typeErr.Field)
+	a.Equal("int", typeErr.Type.String())
+
+	err = json.Unmarshal([]byte(`{"inner":{"n":"x"}}`), &Outer{})
+	a.ErrorAs(err, &typeErr)
+	a.Equal("inner.n", typeErr.Field)
+	a.Equal("int", typeErr.Type.String())
+}
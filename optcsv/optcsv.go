@@ -0,0 +1,238 @@
+// Package optcsv reads and writes CSV records as structs whose fields are
+// optional values, where a blank cell means there's no value.
+//
+// Columns are matched to fields by header name using the `csv:"header"` struct
+// tag. Fields without a tag use the Go field name and fields tagged "-" are
+// ignored. Embedded structs without a tag name are treated as if their fields
+// were declared on the outer struct.
+//
+// Cells are parsed with the field type's encoding.TextUnmarshaler if it has one,
+// otherwise with strconv for basic kinds, and formatted in the same way.
+package optcsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+// Error describes a problem with a single row, or a single cell of a row.
+type Error struct {
+	// Line is the line number of the row, or of the cell if Column is set.
+	Line int
+
+	// Column is the header of the cell, if the problem is with a single cell.
+	Column string
+
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("optcsv: line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("optcsv: line %d, column %q: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Decoder reads structs from CSV records. The first record must be the header.
+type Decoder struct {
+	// Null lists cell values which are treated as empty in addition to blank
+	// cells, such as "NULL" or `\N`.
+	Null []string
+
+	r      *csv.Reader
+	header map[string]int
+
+	// headerErr is kept so that a bad header is reported on every call rather
+	// than the first row being read as the header instead.
+	headerErr error
+}
+
+// NewDecoder creates a Decoder which reads records from `r`.
+func NewDecoder(r *csv.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Decode reads the next record into the struct pointed to by `dst`. It returns
+// io.EOF when there are no more records.
+//
+// Optional fields are left empty if their cell is blank, matches one of the Null
+// values or if their column is missing from the header. Other fields are parsed
+// from the cell as-is and their column is required.
+//
+// If a row can't be decoded the error is an *Error with its line number and
+// the next call to Decode moves on to the following row, so callers may report
+// bad rows and carry on. This includes rows the csv.Reader rejects, such as
+// those with the wrong number of fields, in which case Err is the
+// *csv.ParseError. An error reading the header is returned by every call, since
+// none of the rows can be matched to fields without it.
+func (d *Decoder) Decode(dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return errors.New("optcsv: destination must be a non-nil pointer")
+	}
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("optcsv: destination must point to a struct, got %s", v.Type())
+	}
+
+	if d.headerErr != nil {
+		return d.headerErr
+	}
+	if d.header == nil {
+		header, err := d.r.Read()
+		if err != nil {
+			d.headerErr = readError(err)
+			return d.headerErr
+		}
+		d.header = map[string]int{}
+		for i, h := range header {
+			if _, ok := d.header[h]; !ok {
+				d.header[h] = i
+			}
+		}
+	}
+
+	record, err := d.r.Read()
+	if err != nil {
+		return readError(err)
+	}
+	line, _ := d.r.FieldPos(0)
+
+	fields := optreflect.Fields(v.Type(), "csv")
+	for _, f := range fields {
+		if _, ok := d.header[f.Name]; !ok && !optreflect.IsOptional(f.Type) {
+			return &Error{Line: line, Column: f.Name, Err: errors.New("missing column")}
+		}
+	}
+
+	row := reflect.New(v.Type()).Elem()
+	for _, f := range fields {
+		i, ok := d.header[f.Name]
+		if !ok {
+			continue
+		}
+		// Short rows are only returned by the reader when FieldsPerRecord is
+		// negative, otherwise they're reported as csv.ErrFieldCount.
+		if i >= len(record) {
+			return &Error{Line: line, Column: f.Name, Err: errors.New("missing cell")}
+		}
+
+		if err := d.set(row.FieldByIndex(f.Index), record[i]); err != nil {
+			cellLine, _ := d.r.FieldPos(i)
+			return &Error{Line: cellLine, Column: f.Name, Err: err}
+		}
+	}
+
+	v.Set(row)
+	return nil
+}
+
+// readError wraps an error from the csv.Reader in an *Error with the line the
+// record started on. io.EOF is returned as-is.
+func readError(err error) error {
+	var pe *csv.ParseError
+	if errors.As(err, &pe) {
+		return &Error{Line: pe.StartLine, Err: err}
+	}
+	return err
+}
+
+func (d *Decoder) set(v reflect.Value, cell string) error {
+	if !optreflect.IsOptional(v.Type()) {
+		return optreflect.ParseText(v, cell)
+	}
+
+	if cell == "" {
+		return nil
+	}
+	for _, null := range d.Null {
+		if cell == null {
+			return nil
+		}
+	}
+
+	inner := reflect.New(v.Type().Elem()).Elem()
+	if err := optreflect.ParseText(inner, cell); err != nil {
+		return err
+	}
+	optreflect.Set(v, inner)
+	return nil
+}
+
+// Encoder writes structs as CSV records. The header is written before the
+// first record.
+type Encoder struct {
+	// Null is written for empty optionals, it's blank by default.
+	Null string
+
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewEncoder creates an Encoder which writes records to `w`.
+func NewEncoder(w *csv.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the struct `src`, or a pointer to one, as a record. Empty
+// optionals are written as the Null value. Records are buffered by the
+// underlying csv.Writer so Flush must be called when done.
+func (e *Encoder) Encode(src any) error {
+	v := reflect.ValueOf(src)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return errors.New("optcsv: source is a nil pointer")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("optcsv: source must be a struct, got %s", v.Type())
+	}
+
+	fields := optreflect.Fields(v.Type(), "csv")
+
+	if !e.wroteHeader {
+		header := make([]string, len(fields))
+		for i, f := range fields {
+			header[i] = f.Name
+		}
+		if err := e.w.Write(header); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+
+	record := make([]string, len(fields))
+	for i, f := range fields {
+		fv := v.FieldByIndex(f.Index)
+		if optreflect.IsOptional(f.Type) {
+			inner, ok := optreflect.Get(fv)
+			if !ok {
+				record[i] = e.Null
+				continue
+			}
+			fv = inner
+		}
+
+		cell, err := optreflect.FormatText(fv)
+		if err != nil {
+			return fmt.Errorf("optcsv: column %q: %w", f.Name, err)
+		}
+		record[i] = cell
+	}
+
+	return e.w.Write(record)
+}
+
+// Flush writes any buffered records and returns any error which occurred while
+// writing, see csv.Writer.Flush.
+func (e *Encoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
@@ -0,0 +1,152 @@
+package optcsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Southclaws/opt"
+)
+
+type Audit struct {
+	Created opt.Optional[time.Time] `csv:"created"`
+}
+
+type Person struct {
+	Name    string               `csv:"name"`
+	Age     opt.Optional[int]    `csv:"age"`
+	Email   opt.Optional[string] `csv:"email"`
+	Score   opt.Optional[float64]
+	Ignored opt.Optional[string] `csv:"-"`
+	Audit
+}
+
+func TestDecode(t *testing.T) {
+	a := assert.New(t)
+
+	input := "name,age,email,created,extra\n" +
+		"Southclaws,69,hi@example.com,2023-01-02T03:04:05Z,x\n" +
+		"Someone,,NULL,\\N,\n" +
+		"\"Multi\nLine\",old,,,\n" +
+		"Last,1,,,\n"
+
+	d := NewDecoder(csv.NewReader(strings.NewReader(input)))
+	d.Null = []string{"NULL", `\N`}
+
+	var p Person
+	a.NoError(d.Decode(&p))
+	a.Equal(Person{
+		Name:  "Southclaws",
+		Age:   opt.New(69),
+		Email: opt.New("hi@example.com"),
+		Audit: Audit{Created: opt.New(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC))},
+	}, p)
+
+	a.NoError(d.Decode(&p))
+	a.Equal(Person{Name: "Someone"}, p, "blank and null cells are empty and the row replaces the last")
+
+	err := d.Decode(&p)
+	var csvErr *Error
+	a.ErrorAs(err, &csvErr)
+	a.Equal(5, csvErr.Line, "the line of the cell, after the multi-line name")
+	a.Equal("age", csvErr.Column)
+	a.Contains(err.Error(), `optcsv: line 5, column "age": `)
+	a.Equal(Person{Name: "Someone"}, p, "a bad row leaves the destination untouched")
+
+	a.NoError(d.Decode(&p), "decoding carries on after a bad row")
+	a.Equal(Person{Name: "Last", Age: opt.New(1)}, p)
+
+	a.True(errors.Is(d.Decode(&p), io.EOF))
+}
+
+func TestDecodeMissingColumns(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDecoder(csv.NewReader(strings.NewReader("name\nSouthclaws\n")))
+	var p Person
+	a.NoError(d.Decode(&p))
+	a.Equal(Person{Name: "Southclaws"}, p)
+
+	d = NewDecoder(csv.NewReader(strings.NewReader("age\n1\n")))
+	err := d.Decode(&p)
+	var csvErr *Error
+	a.ErrorAs(err, &csvErr)
+	a.Equal(2, csvErr.Line)
+	a.Equal("name", csvErr.Column)
+
+	r := csv.NewReader(strings.NewReader("name,age\nSouthclaws\n"))
+	r.FieldsPerRecord = -1
+	err = NewDecoder(r).Decode(&p)
+	a.ErrorAs(err, &csvErr)
+	a.Equal("age", csvErr.Column)
+	a.Contains(err.Error(), "missing cell")
+
+	a.Error(d.Decode(p))
+	a.Error(d.Decode(new(int)))
+}
+
+func TestDecodeReaderErrors(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDecoder(csv.NewReader(strings.NewReader("name,age\nShort\nLast,1\n")))
+	var p Person
+	err := d.Decode(&p)
+	var csvErr *Error
+	a.ErrorAs(err, &csvErr)
+	a.Equal(2, csvErr.Line)
+	a.Empty(csvErr.Column)
+	a.True(errors.Is(err, csv.ErrFieldCount))
+	a.Contains(err.Error(), "optcsv: line 2: ")
+
+	a.NoError(d.Decode(&p), "decoding carries on after a row the reader rejects")
+	a.Equal(Person{Name: "Last", Age: opt.New(1)}, p)
+
+	d = NewDecoder(csv.NewReader(strings.NewReader("na\"me\n")))
+	err = d.Decode(&p)
+	a.ErrorAs(err, &csvErr)
+	a.Equal(1, csvErr.Line, "errors in the header are wrapped too")
+	a.True(errors.Is(err, csv.ErrBareQuote))
+
+	d = NewDecoder(csv.NewReader(strings.NewReader("name,\"age\nx\"y,1\nbob,2\n")))
+	first := d.Decode(&p)
+	a.ErrorAs(first, &csvErr)
+	a.Equal(first, d.Decode(&p), "a bad header is reported again instead of reading a row as the header")
+	a.Equal(first, d.Decode(&p))
+}
+
+func TestEncode(t *testing.T) {
+	a := assert.New(t)
+
+	var buf bytes.Buffer
+	e := NewEncoder(csv.NewWriter(&buf))
+
+	a.NoError(e.Encode(Person{
+		Name:  "Southclaws",
+		Age:   opt.New(0),
+		Score: opt.New(1.5),
+	}))
+	e.Null = `\N`
+	a.NoError(e.Encode(&Person{Name: "Someone"}))
+	a.NoError(e.Flush())
+
+	a.Equal("name,age,email,Score,created\n"+
+		"Southclaws,0,,1.5,\n"+
+		"Someone,\\N,\\N,\\N,\\N\n", buf.String())
+
+	d := NewDecoder(csv.NewReader(&buf))
+	d.Null = []string{`\N`}
+	var p Person
+	a.NoError(d.Decode(&p))
+	a.Equal(Person{Name: "Southclaws", Age: opt.New(0), Score: opt.New(1.5)}, p)
+	a.NoError(d.Decode(&p))
+	a.Equal(Person{Name: "Someone"}, p)
+
+	a.Error(e.Encode((*Person)(nil)))
+	a.Error(e.Encode(1))
+}
@@ -0,0 +1,150 @@
+// Package optenv loads configuration structs from environment variables.
+//
+// Variables are mapped using the `env:"NAME"` struct tag and fields without a
+// tag are ignored. Optional fields are left empty when their variable is unset
+// and hold the parsed value when it's set, even if it's set to an empty string.
+// Every other tagged field is required and reported as missing when unset.
+//
+// Nested structs are walked, and a tag on a struct field adds a prefix to the
+// names of the variables inside it:
+//
+//	type Config struct {
+//		Port     opt.Optional[int] `env:"PORT"`
+//		Database struct {
+//			URL     string               `env:"URL"`
+//			Replica opt.Optional[string] `env:"REPLICA_URL"`
+//		} `env:"DB"`
+//	}
+//
+// Here the variables read are PORT, DB_URL and DB_REPLICA_URL.
+//
+// Values are parsed with the type's encoding.TextUnmarshaler if it has one,
+// otherwise with strconv for basic kinds.
+package optenv
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// ErrMissing is wrapped by the Error of a required variable which isn't set.
+var ErrMissing = errors.New("required variable is not set")
+
+// Error describes a problem with a single environment variable.
+type Error struct {
+	Name string
+	Err  error
+}
+
+func (e *Error) Error() string { return fmt.Sprintf("%s: %v", e.Name, e.Err) }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Errors is returned by Load and lists every variable which was missing or
+// couldn't be parsed, so they can all be fixed at once.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "optenv: " + strings.Join(msgs, "; ")
+}
+
+// Loader loads structs using a configurable prefix and lookup function.
+type Loader struct {
+	// Prefix is prepended to the name of every variable, such as "APP_".
+	Prefix string
+
+	// Lookup retrieves a variable and reports whether it's set. If nil,
+	// os.LookupEnv is used.
+	Lookup func(name string) (value string, ok bool)
+}
+
+// Load fills the struct pointed to by `dst` from the environment using
+// os.LookupEnv and no prefix.
+func Load(dst any) error {
+	return Loader{}.Load(dst)
+}
+
+// Load fills the struct pointed to by `dst` from the environment. If any
+// variables are missing or invalid the error is of type Errors.
+func (l Loader) Load(dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return errors.New("optenv: destination must be a non-nil pointer")
+	}
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("optenv: destination must point to a struct, got %s", v.Type())
+	}
+
+	lookup := l.Lookup
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	var errs Errors
+	load(v, l.Prefix, lookup, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func load(v reflect.Value, prefix string, lookup func(string) (string, bool), errs *Errors) {
+	for _, f := range optreflect.Fields(v.Type(), "env") {
+		fv := v.FieldByIndex(f.Index)
+
+		if isNested(f.Type) {
+			p := prefix
+			if f.Tagged {
+				p += f.Name + "_"
+			}
+			load(fv, p, lookup, errs)
+			continue
+		}
+		if !f.Tagged {
+			continue
+		}
+
+		name := prefix + f.Name
+		value, ok := lookup(name)
+		optional := optreflect.IsOptional(f.Type)
+
+		if !ok {
+			if !optional {
+				*errs = append(*errs, &Error{Name: name, Err: ErrMissing})
+			}
+			continue
+		}
+
+		if optional {
+			inner := reflect.New(f.Type.Elem()).Elem()
+			if err := optreflect.ParseText(inner, value); err != nil {
+				*errs = append(*errs, &Error{Name: name, Err: err})
+				continue
+			}
+			optreflect.Set(fv, inner)
+			continue
+		}
+
+		if err := optreflect.ParseText(fv, value); err != nil {
+			*errs = append(*errs, &Error{Name: name, Err: err})
+		}
+	}
+}
+
+// isNested reports whether `t` is a struct to walk rather than a value to parse.
+func isNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
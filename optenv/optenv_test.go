@@ -0,0 +1,124 @@
+package optenv
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Southclaws/opt"
+)
+
+type Database struct {
+	URL     string               `env:"URL"`
+	Replica opt.Optional[string] `env:"REPLICA_URL"`
+}
+
+type Logging struct {
+	Level opt.Optional[string] `env:"LOG_LEVEL"`
+}
+
+type Config struct {
+	Logging
+	Port     opt.Optional[int]     `env:"PORT"`
+	Debug    opt.Optional[bool]    `env:"DEBUG"`
+	Ratio    opt.Optional[float64] `env:"RATIO"`
+	Name     opt.Optional[string]  `env:"NAME"`
+	Secret   string                `env:"SECRET"`
+	Database Database              `env:"DB"`
+	Replicas struct {
+		Count opt.Optional[int] `env:"REPLICA_COUNT"`
+	}
+	Untagged opt.Optional[string]
+}
+
+func lookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+func TestLoad(t *testing.T) {
+	a := assert.New(t)
+
+	var cfg Config
+	err := Loader{Lookup: lookup(map[string]string{
+		"PORT":          "8080",
+		"NAME":          "",
+		"SECRET":        "hunter2",
+		"DB_URL":        "postgres://localhost",
+		"LOG_LEVEL":     "debug",
+		"REPLICA_COUNT": "2",
+		"Untagged":      "ignored",
+	})}.Load(&cfg)
+	a.NoError(err)
+
+	a.Equal(opt.New(8080), cfg.Port)
+	a.Empty(cfg.Debug)
+	a.Empty(cfg.Ratio)
+	a.Equal(opt.New(""), cfg.Name, "set but empty is not the same as unset")
+	a.Equal("hunter2", cfg.Secret)
+	a.Equal("postgres://localhost", cfg.Database.URL)
+	a.Empty(cfg.Database.Replica)
+	a.Equal(opt.New("debug"), cfg.Level)
+	a.Equal(opt.New(2), cfg.Replicas.Count)
+	a.Empty(cfg.Untagged)
+}
+
+func TestLoadPrefix(t *testing.T) {
+	a := assert.New(t)
+
+	var cfg Config
+	err := Loader{Prefix: "APP_", Lookup: lookup(map[string]string{
+		"APP_RATIO":          "0.5",
+		"APP_SECRET":         "hunter2",
+		"APP_DB_URL":         "postgres://localhost",
+		"APP_DB_REPLICA_URL": "postgres://replica",
+		"PORT":               "8080",
+	})}.Load(&cfg)
+	a.NoError(err)
+
+	a.Equal(opt.New(0.5), cfg.Ratio)
+	a.Equal(opt.New("postgres://replica"), cfg.Database.Replica)
+	a.Empty(cfg.Port)
+}
+
+func TestLoadErrors(t *testing.T) {
+	a := assert.New(t)
+
+	var cfg Config
+	err := Loader{Lookup: lookup(map[string]string{
+		"PORT":  "http",
+		"DEBUG": "yes",
+	})}.Load(&cfg)
+
+	var errs Errors
+	a.ErrorAs(err, &errs)
+	a.Len(errs, 4)
+
+	a.Equal("PORT", errs[0].Name)
+	a.Equal("DEBUG", errs[1].Name)
+	a.Equal("SECRET", errs[2].Name)
+	a.True(errors.Is(errs[2], ErrMissing))
+	a.Equal("DB_URL", errs[3].Name)
+	a.Contains(err.Error(), "optenv: PORT: ")
+	a.Contains(err.Error(), "SECRET: required variable is not set")
+
+	a.Error(Load(cfg))
+	a.Error(Load(new(string)))
+}
+
+func TestLoadOS(t *testing.T) {
+	a := assert.New(t)
+
+	t.Setenv("OPTENV_TEST_PORT", "9000")
+
+	var cfg struct {
+		Port opt.Optional[int]    `env:"OPTENV_TEST_PORT"`
+		Host opt.Optional[string] `env:"OPTENV_TEST_HOST"`
+	}
+	a.NoError(Load(&cfg))
+	a.Equal(opt.New(9000), cfg.Port)
+	a.Empty(cfg.Host)
+}
@@ -0,0 +1,63 @@
+// Package optflag registers command-line flags which produce optional values.
+//
+// Each function defines a flag on the given flag.FlagSet, or flag.CommandLine
+// if it's nil, and returns a pointer to an optional which stays empty unless the
+// flag is passed. This mirrors the functions of the flag package, except there
+// is no default value as that's expressed by the optional being empty:
+//
+//	port := optflag.Int(nil, "port", "port to listen on")
+//	flag.Parse()
+//
+//	addr := fmt.Sprintf(":%d", port.Or(cfg.Port))
+package optflag
+
+import (
+	"flag"
+
+	"github.com/Southclaws/opt"
+)
+
+// Var defines a flag of any type which Optional.UnmarshalText can parse.
+func Var[T any](fs *flag.FlagSet, name, usage string) *opt.Optional[T] {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	o := new(opt.Optional[T])
+	fs.Var(opt.NewFlag(o), name, usage)
+	return o
+}
+
+// Bool defines a bool flag, which may be passed without a value.
+func Bool(fs *flag.FlagSet, name, usage string) *opt.Optional[bool] {
+	return Var[bool](fs, name, usage)
+}
+
+// Int defines an int flag.
+func Int(fs *flag.FlagSet, name, usage string) *opt.Optional[int] {
+	return Var[int](fs, name, usage)
+}
+
+// Int64 defines an int64 flag.
+func Int64(fs *flag.FlagSet, name, usage string) *opt.Optional[int64] {
+	return Var[int64](fs, name, usage)
+}
+
+// Uint defines a uint flag.
+func Uint(fs *flag.FlagSet, name, usage string) *opt.Optional[uint] {
+	return Var[uint](fs, name, usage)
+}
+
+// Uint64 defines a uint64 flag.
+func Uint64(fs *flag.FlagSet, name, usage string) *opt.Optional[uint64] {
+	return Var[uint64](fs, name, usage)
+}
+
+// Float64 defines a float64 flag.
+func Float64(fs *flag.FlagSet, name, usage string) *opt.Optional[float64] {
+	return Var[float64](fs, name, usage)
+}
+
+// String defines a string flag.
+func String(fs *flag.FlagSet, name, usage string) *opt.Optional[string] {
+	return Var[string](fs, name, usage)
+}
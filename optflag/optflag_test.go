@@ -0,0 +1,51 @@
+package optflag
+
+import (
+	"flag"
+	"io"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Southclaws/opt"
+)
+
+func TestFlags(t *testing.T) {
+	a := assert.New(t)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	verbose := Bool(fs, "verbose", "")
+	port := Int(fs, "port", "")
+	offset := Int64(fs, "offset", "")
+	workers := Uint(fs, "workers", "")
+	limit := Uint64(fs, "limit", "")
+	ratio := Float64(fs, "ratio", "")
+	host := String(fs, "host", "")
+	addr := Var[netip.Addr](fs, "addr", "")
+
+	a.NoError(fs.Parse([]string{"-verbose", "-port", "0", "-limit=10", "-host", "", "-addr", "127.0.0.1"}))
+
+	a.Equal(opt.New(true), *verbose)
+	a.Equal(opt.New(0), *port, "passing the zero value is not the same as not passing")
+	a.Empty(*offset)
+	a.Empty(*workers)
+	a.Equal(opt.New(uint64(10)), *limit)
+	a.Empty(*ratio)
+	a.Equal(opt.New(""), *host)
+	a.Equal(opt.New(netip.MustParseAddr("127.0.0.1")), *addr)
+
+	a.Error(fs.Parse([]string{"-workers", "-1"}))
+}
+
+func TestCommandLine(t *testing.T) {
+	a := assert.New(t)
+
+	name := "optflag-test-port"
+	port := Int(nil, name, "")
+	a.NotNil(flag.CommandLine.Lookup(name))
+	a.NoError(flag.CommandLine.Set(name, "8080"))
+	a.Equal(opt.New(8080), *port)
+}
@@ -0,0 +1,227 @@
+// Package optform binds URL query strings and HTML forms to structs whose fields
+// are optional values, and encodes such structs back into url.Values.
+//
+// Keys are mapped using the `form:"name"` struct tag, fields without a tag use
+// the Go field name and fields tagged "-" are ignored. Embedded structs without
+// a tag name are treated as if their fields were declared on the outer struct.
+//
+// Values are parsed with the field type's encoding.TextUnmarshaler if it has
+// one, otherwise with strconv for basic kinds. Bool fields also accept "on",
+// which browsers send for checked checkboxes, and an empty value, so a bare
+// `?exact` is true. Slice fields, including `opt.Optional[[]T]`, receive every
+// value of a repeated key.
+package optform
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"reflect"
+
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+)
+
+// Error is returned when the value of a key can't be parsed into its field.
+type Error struct {
+	Key string
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("optform: invalid value for %q: %v", e.Key, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Decode fills the struct pointed to by `dst` from `values`, such as the result
+// of `r.URL.Query()` or `r.PostForm`. Optional fields whose key is absent are
+// left empty and present keys are parsed into the wrapped type. Other fields are
+// only modified if their key is present.
+//
+//	type Search struct {
+//		Query opt.Optional[string]   `form:"q"`
+//		Page  opt.Optional[int]      `form:"page"`
+//		Tags  opt.Optional[[]string] `form:"tag"`
+//	}
+//
+//	var s Search
+//	err := optform.Decode(r.URL.Query(), &s)
+func Decode(values url.Values, dst any) error {
+	return decode(values, nil, dst)
+}
+
+// DecodeMultipart is like Decode but reads from a parsed multipart form, such
+// as `r.MultipartForm`. In addition to the kinds supported by Decode, fields of
+// type `*multipart.FileHeader` and `[]*multipart.FileHeader`, optional or not,
+// are filled from the uploaded files.
+func DecodeMultipart(form *multipart.Form, dst any) error {
+	if form == nil {
+		return decode(nil, nil, dst)
+	}
+	return decode(form.Value, form.File, dst)
+}
+
+func decode(values map[string][]string, files map[string][]*multipart.FileHeader, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return errors.New("optform: destination must be a non-nil pointer")
+	}
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("optform: destination must point to a struct, got %s", v.Type())
+	}
+
+	for _, f := range optreflect.Fields(v.Type(), "form") {
+		fv := v.FieldByIndex(f.Index)
+
+		if isFileField(f.Type) {
+			if fh, ok := files[f.Name]; ok && len(fh) > 0 {
+				setFiles(fv, fh)
+			}
+			continue
+		}
+
+		vs, ok := values[f.Name]
+		if !ok || len(vs) == 0 {
+			continue
+		}
+		if err := set(fv, vs); err != nil {
+			return &Error{Key: f.Name, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func set(v reflect.Value, values []string) error {
+	if optreflect.IsOptional(v.Type()) {
+		inner := reflect.New(v.Type().Elem()).Elem()
+		if err := set(inner, values); err != nil {
+			return err
+		}
+		optreflect.Set(v, inner)
+		return nil
+	}
+
+	if v.Kind() == reflect.Slice && !reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		s := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := parse(s.Index(i), value); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+
+	return parse(v, values[0])
+}
+
+// parse is optreflect.ParseText with the bool spellings used by HTML forms: a
+// checkbox without a value attribute submits "on" and a bare key such as
+// `?exact` has an empty value, both of which mean true.
+func parse(v reflect.Value, value string) error {
+	if v.Kind() == reflect.Bool && !reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		if value == "" || value == "on" {
+			v.SetBool(true)
+			return nil
+		}
+	}
+	return optreflect.ParseText(v, value)
+}
+
+func isFileField(t reflect.Type) bool {
+	if optreflect.IsOptional(t) {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t == fileHeaderType
+}
+
+func setFiles(v reflect.Value, files []*multipart.FileHeader) {
+	if optreflect.IsOptional(v.Type()) {
+		inner := reflect.New(v.Type().Elem()).Elem()
+		setFiles(inner, files)
+		optreflect.Set(v, inner)
+		return
+	}
+
+	if v.Kind() == reflect.Slice {
+		v.Set(reflect.ValueOf(append([]*multipart.FileHeader{}, files...)))
+		return
+	}
+
+	v.Set(reflect.ValueOf(files[0]))
+}
+
+// Encode converts the struct `src`, or a pointer to one, into url.Values using
+// the same key mapping as Decode. Empty optional fields are left out, slices are
+// written as repeated keys and other values are formatted with their
+// encoding.TextMarshaler or strconv. File fields are not encoded.
+func Encode(src any) (url.Values, error) {
+	v := reflect.ValueOf(src)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, errors.New("optform: source is a nil pointer")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("optform: source must be a struct, got %s", v.Type())
+	}
+
+	values := url.Values{}
+	for _, f := range optreflect.Fields(v.Type(), "form") {
+		if isFileField(f.Type) {
+			continue
+		}
+
+		vs, err := format(v.FieldByIndex(f.Index))
+		if err != nil {
+			return nil, &Error{Key: f.Name, Err: err}
+		}
+		if len(vs) > 0 {
+			values[f.Name] = vs
+		}
+	}
+
+	return values, nil
+}
+
+func format(v reflect.Value) ([]string, error) {
+	if optreflect.IsOptional(v.Type()) {
+		inner, ok := optreflect.Get(v)
+		if !ok {
+			return nil, nil
+		}
+		return format(inner)
+	}
+
+	if v.Kind() == reflect.Slice && !v.Type().Implements(textMarshalerType) {
+		values := make([]string, v.Len())
+		for i := range values {
+			s, err := optreflect.FormatText(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values[i] = s
+		}
+		return values, nil
+	}
+
+	s, err := optreflect.FormatText(v)
+	if err != nil {
+		return nil, err
+	}
+	return []string{s}, nil
+}
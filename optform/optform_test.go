@@ -0,0 +1,173 @@
+package optform
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Southclaws/opt"
+)
+
+type Paging struct {
+	Page  opt.Optional[int] `form:"page"`
+	Limit opt.Optional[int] `form:"limit"`
+}
+
+type Search struct {
+	Paging
+	Query   opt.Optional[string]    `form:"q"`
+	Tags    opt.Optional[[]string]  `form:"tag"`
+	IDs     []int                   `form:"id"`
+	Since   opt.Optional[time.Time] `form:"since"`
+	Exact   bool                    `form:"exact"`
+	Sort    string
+	Ignored opt.Optional[string] `form:"-"`
+}
+
+func TestDecode(t *testing.T) {
+	a := assert.New(t)
+
+	values, err := url.ParseQuery("q=cats&page=2&tag=a&tag=b&id=1&id=2&since=2023-01-02T03:04:05Z&exact=true&Sort=new&Ignored=x")
+	a.NoError(err)
+
+	var s Search
+	a.NoError(Decode(values, &s))
+
+	a.Equal(opt.New("cats"), s.Query)
+	a.Equal(opt.New(2), s.Page)
+	a.Empty(s.Limit)
+	a.Equal(opt.New([]string{"a", "b"}), s.Tags)
+	a.Equal([]int{1, 2}, s.IDs)
+	a.Equal(opt.New(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)), s.Since)
+	a.True(s.Exact)
+	a.Equal("new", s.Sort)
+	a.Empty(s.Ignored)
+}
+
+func TestDecodeEmpty(t *testing.T) {
+	a := assert.New(t)
+
+	s := Search{Sort: "old"}
+	a.NoError(Decode(url.Values{"q": {""}}, &s))
+
+	a.Equal(opt.New(""), s.Query, "present keys are set even if blank")
+	a.Empty(s.Page)
+	a.Empty(s.Tags)
+	a.Nil(s.IDs)
+	a.Equal("old", s.Sort, "absent keys leave fields untouched")
+}
+
+func TestDecodeBool(t *testing.T) {
+	a := assert.New(t)
+
+	type Filter struct {
+		Exact   bool               `form:"exact"`
+		Archive opt.Optional[bool] `form:"archive"`
+		Flags   []bool             `form:"flag"`
+		Draft   opt.Optional[bool] `form:"draft"`
+	}
+
+	values, err := url.ParseQuery("exact&archive=on&flag=on&flag=false&flag=&draft=false")
+	a.NoError(err)
+
+	var f Filter
+	a.NoError(Decode(values, &f))
+	a.True(f.Exact, "a bare key is true")
+	a.Equal(opt.New(true), f.Archive, "checkboxes send on")
+	a.Equal([]bool{true, false, true}, f.Flags)
+	a.Equal(opt.New(false), f.Draft)
+
+	a.Error(Decode(url.Values{"exact": {"off"}}, &f))
+}
+
+func TestDecodeErrors(t *testing.T) {
+	a := assert.New(t)
+
+	var s Search
+	err := Decode(url.Values{"page": {"two"}}, &s)
+	var formErr *Error
+	a.ErrorAs(err, &formErr)
+	a.Equal("page", formErr.Key)
+	a.Contains(err.Error(), `invalid value for "page"`)
+
+	err = Decode(url.Values{"id": {"1", "x"}}, &s)
+	a.ErrorAs(err, &formErr)
+	a.Equal("id", formErr.Key)
+
+	a.Error(Decode(url.Values{}, s))
+	a.Error(Decode(url.Values{}, (*Search)(nil)))
+	a.Error(Decode(url.Values{}, new(int)))
+}
+
+func TestDecodeMultipart(t *testing.T) {
+	a := assert.New(t)
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	a.NoError(w.WriteField("title", "holiday"))
+	for _, file := range [][2]string{{"photos", "a.jpg"}, {"photos", "b.jpg"}, {"cover", "c.jpg"}} {
+		fw, err := w.CreateFormFile(file[0], file[1])
+		a.NoError(err)
+		_, err = fw.Write([]byte(file[1]))
+		a.NoError(err)
+	}
+	a.NoError(w.Close())
+
+	r := httptest.NewRequest("POST", "/", &body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	a.NoError(r.ParseMultipartForm(1 << 20))
+
+	var upload struct {
+		Title  opt.Optional[string]                `form:"title"`
+		Photos []*multipart.FileHeader             `form:"photos"`
+		Cover  opt.Optional[*multipart.FileHeader] `form:"cover"`
+		Avatar opt.Optional[*multipart.FileHeader] `form:"avatar"`
+	}
+	a.NoError(DecodeMultipart(r.MultipartForm, &upload))
+
+	a.Equal(opt.New("holiday"), upload.Title)
+	a.Len(upload.Photos, 2)
+	a.Equal("b.jpg", upload.Photos[1].Filename)
+	cover, ok := upload.Cover.Get()
+	a.True(ok)
+	a.Equal("c.jpg", cover.Filename)
+	a.Empty(upload.Avatar)
+}
+
+func TestEncode(t *testing.T) {
+	a := assert.New(t)
+
+	values, err := Encode(Search{
+		Paging: Paging{Page: opt.New(0)},
+		Query:  opt.New("cats"),
+		Tags:   opt.New([]string{"a", "b"}),
+		IDs:    []int{3},
+		Since:  opt.New(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Sort:   "new",
+	})
+	a.NoError(err)
+	a.Equal(url.Values{
+		"page":  {"0"},
+		"q":     {"cats"},
+		"tag":   {"a", "b"},
+		"id":    {"3"},
+		"since": {"2023-01-02T03:04:05Z"},
+		"exact": {"false"},
+		"Sort":  {"new"},
+	}, values)
+
+	var s Search
+	a.NoError(Decode(values, &s))
+	a.Equal(opt.New(0), s.Page)
+	a.Empty(s.Limit)
+
+	_, err = Encode((*Search)(nil))
+	a.Error(err)
+	_, err = Encode(1)
+	a.Error(err)
+}
@@ -0,0 +1,94 @@
+// Package opthttp provides typed accessors for the parts of an HTTP request
+// which may or may not be present, such as query parameters and headers.
+//
+// Every accessor returns an empty optional if the value is missing and an error
+// only if the value is present but can't be parsed. Values are parsed with the
+// type's encoding.TextUnmarshaler if it has one, otherwise with strconv for
+// basic kinds, just like Optional.UnmarshalText.
+//
+//	page, err := opthttp.Query[int](r, "page")
+//	if err != nil {
+//		http.Error(w, err.Error(), http.StatusBadRequest)
+//		return
+//	}
+//	offset := page.Or(1) * pageSize
+package opthttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/Southclaws/opt"
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+// defaultMaxMemory is the same limit used by http.Request.FormValue.
+const defaultMaxMemory = 32 << 20
+
+// Error is returned when a value is present in the request but can't be parsed.
+type Error struct {
+	// Source is where the value came from: "query", "header", "cookie", "path"
+	// or "form".
+	Source string
+	Name   string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("opthttp: invalid %s value for %q: %v", e.Source, e.Name, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Query returns the first value of the URL query parameter `key`.
+func Query[T any](r *http.Request, key string) (opt.Optional[T], error) {
+	values, ok := r.URL.Query()[key]
+	return parse[T]("query", key, values, ok)
+}
+
+// Header returns the first value of the header `name`. The name is canonicalised
+// in the same way as http.Header.Get.
+func Header[T any](r *http.Request, name string) (opt.Optional[T], error) {
+	values := r.Header.Values(name)
+	return parse[T]("header", name, values, len(values) > 0)
+}
+
+// Cookie returns the value of the cookie `name`.
+func Cookie[T any](r *http.Request, name string) (opt.Optional[T], error) {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return nil, nil
+	}
+	return parse[T]("cookie", name, []string{c.Value}, true)
+}
+
+// FormValue returns the first value of the form field `key`, from either the
+// URL query or the request body, like http.Request.FormValue. The form is parsed
+// if it hasn't been already and an error is returned if the body is malformed.
+func FormValue[T any](r *http.Request, key string) (opt.Optional[T], error) {
+	if r.Form == nil {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		err := r.ParseMultipartForm(defaultMaxMemory)
+		if err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return nil, err
+		}
+	}
+	values, ok := r.Form[key]
+	return parse[T]("form", key, values, ok)
+}
+
+func parse[T any](source, name string, values []string, ok bool) (opt.Optional[T], error) {
+	if !ok || len(values) == 0 {
+		return nil, nil
+	}
+
+	var v T
+	if err := optreflect.ParseText(reflect.ValueOf(&v).Elem(), values[0]); err != nil {
+		return nil, &Error{Source: source, Name: name, Err: err}
+	}
+	return opt.New(v), nil
+}
@@ -0,0 +1,108 @@
+package opthttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Southclaws/opt"
+)
+
+func TestQuery(t *testing.T) {
+	a := assert.New(t)
+	r := httptest.NewRequest("GET", "/?page=2&page=3&q=&bad=x", nil)
+
+	page, err := Query[int](r, "page")
+	a.NoError(err)
+	a.Equal(opt.New(2), page)
+
+	q, err := Query[string](r, "q")
+	a.NoError(err)
+	a.Equal(opt.New(""), q, "present but blank is not missing")
+
+	limit, err := Query[int](r, "limit")
+	a.NoError(err)
+	a.Empty(limit)
+
+	bad, err := Query[int](r, "bad")
+	a.Empty(bad)
+	var httpErr *Error
+	a.ErrorAs(err, &httpErr)
+	a.Equal("query", httpErr.Source)
+	a.Equal("bad", httpErr.Name)
+	a.Contains(err.Error(), `invalid query value for "bad"`)
+}
+
+func TestHeader(t *testing.T) {
+	a := assert.New(t)
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Tenant", "42")
+	r.Header.Set("If-Modified-Since", "2023-01-02T03:04:05Z")
+
+	tenant, err := Header[int](r, "x-tenant")
+	a.NoError(err)
+	a.Equal(opt.New(42), tenant)
+
+	since, err := Header[time.Time](r, "If-Modified-Since")
+	a.NoError(err)
+	a.Equal(opt.New(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)), since)
+
+	missing, err := Header[string](r, "X-Missing")
+	a.NoError(err)
+	a.Empty(missing)
+
+	_, err = Header[bool](r, "X-Tenant")
+	a.Error(err)
+}
+
+func TestCookie(t *testing.T) {
+	a := assert.New(t)
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	r.AddCookie(&http.Cookie{Name: "visits", Value: "3"})
+
+	session, err := Cookie[string](r, "session")
+	a.NoError(err)
+	a.Equal(opt.New("abc"), session)
+
+	visits, err := Cookie[uint](r, "visits")
+	a.NoError(err)
+	a.Equal(opt.New(uint(3)), visits)
+
+	missing, err := Cookie[string](r, "theme")
+	a.NoError(err)
+	a.Empty(missing)
+
+	_, err = Cookie[int](r, "session")
+	a.Error(err)
+}
+
+func TestFormValue(t *testing.T) {
+	a := assert.New(t)
+	r := httptest.NewRequest("POST", "/?source=query", strings.NewReader("age=30&name=Southclaws&bad=x"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	age, err := FormValue[int](r, "age")
+	a.NoError(err)
+	a.Equal(opt.New(30), age)
+
+	source, err := FormValue[string](r, "source")
+	a.NoError(err)
+	a.Equal(opt.New("query"), source)
+
+	missing, err := FormValue[string](r, "email")
+	a.NoError(err)
+	a.Empty(missing)
+
+	_, err = FormValue[int](r, "bad")
+	a.Error(err)
+
+	r = httptest.NewRequest("POST", "/", strings.NewReader("%zz"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, err = FormValue[string](r, "age")
+	a.Error(err)
+}
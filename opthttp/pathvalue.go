@@ -0,0 +1,18 @@
+//go:build go1.22
+
+package opthttp
+
+import (
+	"net/http"
+
+	"github.com/Southclaws/opt"
+)
+
+// PathValue returns the value of the wildcard `name` matched by the
+// http.ServeMux pattern, see http.Request.PathValue. As the standard library
+// doesn't distinguish between a missing wildcard and one which matched nothing,
+// both of these produce an empty optional.
+func PathValue[T any](r *http.Request, name string) (opt.Optional[T], error) {
+	value := r.PathValue(name)
+	return parse[T]("path", name, []string{value}, value != "")
+}
@@ -0,0 +1,31 @@
+//go:build go1.22
+
+package opthttp
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Southclaws/opt"
+)
+
+func TestPathValue(t *testing.T) {
+	a := assert.New(t)
+	r := httptest.NewRequest("GET", "/users/5", nil)
+	r.SetPathValue("id", "5")
+
+	id, err := PathValue[int](r, "id")
+	a.NoError(err)
+	a.Equal(opt.New(5), id)
+
+	missing, err := PathValue[string](r, "name")
+	a.NoError(err)
+	a.Empty(missing)
+
+	_, err = PathValue[bool](r, "id")
+	var httpErr *Error
+	a.ErrorAs(err, &httpErr)
+	a.Equal("path", httpErr.Source)
+}
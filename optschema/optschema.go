@@ -0,0 +1,301 @@
+// Package optschema generates JSON Schema (draft 2020-12) and OpenAPI 3.1
+// component schemas from Go types, understanding that optional values are
+// nullable and may be left out.
+//
+// Generic schema generators see `opt.Optional[T]` as an array, since that's its
+// underlying type. Here an optional field is given the schema of `T` with
+// "null" added to its types and it's left out of the object's "required" list.
+// The other optional types in opt are handled in the same way, see Unwrap.
+// Other generators can do the same by calling Unwrap and Nullable from their
+// own type mapping hooks.
+//
+// Struct fields are named and skipped following the same rules as
+// encoding/json. Fields which aren't optional are required unless they have the
+// `omitempty` or `omitzero` option.
+package optschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+// Draft is the URI of the JSON Schema dialect which is generated.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema, as used by both JSON Schema 2020-12 and OpenAPI 3.1.
+// Only the keywords which this package generates are included.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Types is the value of the "type" keyword. A single type is encoded as a
+// string and several types are encoded as an array.
+type Types []string
+
+// MarshalJSON encodes a single type as a string and several as an array.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON decodes either a string or an array of strings.
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = Types{s}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// Generate produces a JSON Schema document for the type `T`. Types which refer
+// to themselves are placed in "$defs" and referenced, everything else is
+// inlined.
+func Generate[T any]() *Schema {
+	return Reflect(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Reflect is the non-generic version of Generate.
+func Reflect(t reflect.Type) *Schema {
+	g := newGenerator("#/$defs/")
+	s := *g.schema(t)
+	s.Schema = Draft
+	if len(g.defs) > 0 {
+		s.Defs = g.defs
+	}
+	return &s
+}
+
+// Components produces the schemas for the "components/schemas" section of an
+// OpenAPI 3.1 document, keyed by type name, for the type of each of `values`.
+// Types which refer to themselves are referenced with "#/components/schemas/"
+// and everything else is inlined. If types from different packages share a
+// name, the later ones are numbered, such as `User2`.
+//
+//	spec.Components.Schemas = optschema.Components(User{}, Post{})
+func Components(values ...any) map[string]*Schema {
+	g := newGenerator("#/components/schemas/")
+	for _, v := range values {
+		t := reflect.TypeOf(v)
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if s := g.schema(t); s.Ref == "" {
+			g.defs[g.name(t)] = s
+		}
+	}
+	return g.defs
+}
+
+// Unwrap reports whether `t` is one of the optional types from the opt package
+// and returns the type it wraps. Other schema generators can use it to treat
+// optionals as a nullable `T` which isn't required.
+//
+// `opt.Optional[T]`, `opt.Nullable[T]`, `opt.BlankAsEmpty[T]`,
+// `opt.ZeroAsEmpty[T]`, `opt.EmptyWhen[T, P]` and `opt.Lenient[T]` all unwrap
+// to `T`. `opt.StringEncoded[T]` unwraps to string, since that's how its value
+// is encoded.
+func Unwrap(t reflect.Type) (elem reflect.Type, ok bool) {
+	name, ok := optreflect.TypeName(t)
+	if !ok {
+		return nil, false
+	}
+
+	switch {
+	case optreflect.IsNullable(t):
+		return t.Elem().Elem(), true
+
+	case t.Kind() == reflect.Slice && (name == "Optional" || name == "BlankAsEmpty" || name == "ZeroAsEmpty" || name == "EmptyWhen"):
+		return t.Elem(), true
+
+	case t.Kind() == reflect.Slice && name == "StringEncoded":
+		return stringType, true
+
+	case t.Kind() == reflect.Struct && name == "Lenient" && t.NumField() > 0 && optreflect.IsOptional(t.Field(0).Type):
+		return t.Field(0).Type.Elem(), true
+	}
+	return nil, false
+}
+
+// Nullable returns a copy of `s` which also permits null.
+func Nullable(s *Schema) *Schema {
+	switch {
+	case s.Ref != "":
+		return &Schema{AnyOf: []*Schema{s, {Type: Types{"null"}}}}
+
+	case len(s.Type) == 0:
+		// An empty schema already permits anything, including null.
+		return s
+	}
+
+	n := *s
+	for _, t := range s.Type {
+		if t == "null" {
+			return &n
+		}
+	}
+	n.Type = append(append(Types{}, s.Type...), "null")
+	return &n
+}
+
+// Name returns the name used for `t` in "$defs" or OpenAPI components. Package
+// paths are dropped from the type arguments of generic types, so
+// `Page[example.com/models.User]` becomes `Page_User`. A number is appended if
+// another type in the same document already has the name.
+func Name(t reflect.Type) string {
+	parts := strings.FieldsFunc(t.Name(), func(r rune) bool {
+		return r == '[' || r == ']' || r == ','
+	})
+	for i, p := range parts {
+		if j := strings.LastIndex(p, "."); j >= 0 {
+			parts[i] = p[j+1:]
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	stringType        = reflect.TypeOf("")
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+type generator struct {
+	refPrefix string
+	defs      map[string]*Schema
+	visiting  map[reflect.Type]bool
+	recursive map[reflect.Type]bool
+
+	// names holds the name given to each type in defs and taken holds the
+	// names in use, since Name alone doesn't tell packages apart.
+	names map[reflect.Type]string
+	taken map[string]bool
+}
+
+func newGenerator(refPrefix string) *generator {
+	return &generator{
+		refPrefix: refPrefix,
+		defs:      map[string]*Schema{},
+		visiting:  map[reflect.Type]bool{},
+		recursive: map[reflect.Type]bool{},
+		names:     map[reflect.Type]string{},
+		taken:     map[string]bool{},
+	}
+}
+
+// name returns the name of `t` in defs, which is Name(t) with a number
+// appended if another type already has that name.
+func (g *generator) name(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := Name(t)
+	for i := 2; g.taken[name]; i++ {
+		name = fmt.Sprintf("%s%d", Name(t), i)
+	}
+	g.names[t], g.taken[name] = name, true
+	return name
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	if elem, ok := Unwrap(t); ok {
+		return Nullable(g.schema(elem))
+	}
+	if t.Kind() == reflect.Pointer {
+		return Nullable(g.schema(t.Elem()))
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	case implements(t, marshalerType):
+		// The JSON representation is unknown so anything is permitted.
+		return &Schema{}
+	case implements(t, textMarshalerType):
+		return &Schema{Type: Types{"string"}}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: Types{"integer"}}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		zero := 0
+		return &Schema{Type: Types{"integer"}, Minimum: &zero}
+
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: Types{"string"}, ContentEncoding: "base64"}
+		}
+		return &Schema{Type: Types{"array"}, Items: g.schema(t.Elem())}
+
+	case reflect.Array:
+		return &Schema{Type: Types{"array"}, Items: g.schema(t.Elem())}
+
+	case reflect.Map:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: g.schema(t.Elem())}
+
+	case reflect.Struct:
+		return g.object(t)
+	}
+
+	return &Schema{}
+}
+
+func (g *generator) object(t reflect.Type) *Schema {
+	if g.visiting[t] {
+		g.recursive[t] = true
+		return &Schema{Ref: g.refPrefix + g.name(t)}
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	s := &Schema{Type: Types{"object"}, Properties: map[string]*Schema{}}
+	for _, f := range optreflect.Fields(t, "json") {
+		s.Properties[f.Name] = g.schema(f.Type)
+
+		_, optional := Unwrap(f.Type)
+		if !optional && !f.HasOption("omitempty") && !f.HasOption("omitzero") {
+			s.Required = append(s.Required, f.Name)
+		}
+	}
+
+	if g.recursive[t] {
+		g.defs[g.name(t)] = s
+		return &Schema{Ref: g.refPrefix + g.name(t)}
+	}
+	return s
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
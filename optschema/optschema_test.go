@@ -0,0 +1,221 @@
+package optschema
+
+import (
+	"encoding/json"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Southclaws/opt"
+)
+
+type Address struct {
+	Street string               `json:"street"`
+	City   opt.Optional[string] `json:"city"`
+}
+
+type Timestamps struct {
+	Created time.Time `json:"created"`
+}
+
+type User struct {
+	Timestamps
+	ID       int64                 `json:"id"`
+	Name     string                `json:"name"`
+	Email    opt.Optional[string]  `json:"email"`
+	Age      opt.Optional[uint8]   `json:"age"`
+	Score    float64               `json:"score,omitempty"`
+	Tags     []string              `json:"tags"`
+	Address  opt.Optional[Address] `json:"address"`
+	Manager  *User                 `json:"manager"`
+	Cleared  opt.Nullable[bool]    `json:"cleared"`
+	IP       netip.Addr            `json:"ip"`
+	Avatar   []byte                `json:"avatar"`
+	Meta     map[string]any        `json:"meta"`
+	Password string                `json:"-"`
+	Raw      json.RawMessage       `json:"raw,omitzero"`
+}
+
+func marshal(t *testing.T, v any) string {
+	b, err := json.MarshalIndent(v, "", "  ")
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestGenerate(t *testing.T) {
+	a := assert.New(t)
+
+	a.JSONEq(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$ref": "#/$defs/User",
+		"$defs": {
+			"User": {
+				"type": "object",
+				"properties": {
+					"created": {"type": "string", "format": "date-time"},
+					"id": {"type": "integer"},
+					"name": {"type": "string"},
+					"email": {"type": ["string", "null"]},
+					"age": {"type": ["integer", "null"], "minimum": 0},
+					"score": {"type": "number"},
+					"tags": {"type": "array", "items": {"type": "string"}},
+					"address": {
+						"type": ["object", "null"],
+						"properties": {
+							"street": {"type": "string"},
+							"city": {"type": ["string", "null"]}
+						},
+						"required": ["street"]
+					},
+					"manager": {"anyOf": [{"$ref": "#/$defs/User"}, {"type": "null"}]},
+					"cleared": {"type": ["boolean", "null"]},
+					"ip": {"type": "string"},
+					"avatar": {"type": "string", "contentEncoding": "base64"},
+					"meta": {"type": "object", "additionalProperties": {}},
+					"raw": {}
+				},
+				"required": ["id", "name", "tags", "manager", "ip", "avatar", "meta", "created"]
+			}
+		}
+	}`, marshal(t, Generate[User]()))
+
+	a.JSONEq(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"street": {"type": "string"},
+			"city": {"type": ["string", "null"]}
+		},
+		"required": ["street"]
+	}`, marshal(t, Generate[Address]()))
+
+	a.JSONEq(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": ["integer", "null"]
+	}`, marshal(t, Reflect(reflect.TypeOf(opt.Optional[int]{}))))
+}
+
+func TestComponents(t *testing.T) {
+	a := assert.New(t)
+
+	components := Components(User{}, &Address{})
+	a.Len(components, 2)
+	a.Equal("#/components/schemas/User", components["User"].Properties["manager"].AnyOf[0].Ref)
+	a.Equal(Types{"string", "null"}, components["Address"].Properties["city"].Type)
+	a.Equal([]string{"street"}, components["Address"].Required)
+}
+
+func TestComponentsNameCollisions(t *testing.T) {
+	a := assert.New(t)
+
+	outer := Address{}
+
+	// Declared here so it has the same name as the package level Address but
+	// is a different type, like a type of the same name from another package.
+	type Address struct {
+		Postcode string `json:"postcode"`
+	}
+	type Node struct {
+		Parent *Node `json:"parent"`
+	}
+
+	components := Components(outer, Address{}, Node{})
+	a.Contains(components["Address"].Properties, "street")
+	a.Contains(components["Address2"].Properties, "postcode")
+	a.Equal("#/components/schemas/Node", components["Node"].Properties["parent"].AnyOf[0].Ref)
+
+	s := Generate[struct {
+		A Address            `json:"a"`
+		B opt.Optional[Node] `json:"b"`
+	}]()
+	a.Contains(s.Properties["a"].Properties, "postcode")
+	a.Equal("#/$defs/Node", s.Properties["b"].AnyOf[0].Ref)
+	a.Contains(s.Defs, "Node")
+}
+
+func TestUnwrap(t *testing.T) {
+	a := assert.New(t)
+
+	elem, ok := Unwrap(reflect.TypeOf(opt.Optional[Address]{}))
+	a.True(ok)
+	a.Equal(reflect.TypeOf(Address{}), elem)
+
+	elem, ok = Unwrap(reflect.TypeOf(opt.Nullable[int]{}))
+	a.True(ok)
+	a.Equal(reflect.TypeOf(0), elem)
+
+	elem, ok = Unwrap(reflect.TypeOf(opt.StringEncoded[int]{}))
+	a.True(ok)
+	a.Equal(reflect.TypeOf(""), elem, "string encoded values are strings")
+
+	for _, v := range []any{
+		opt.BlankAsEmpty[string]{},
+		opt.ZeroAsEmpty[string]{},
+		opt.EmptyWhen[string, opt.Blank[string]]{},
+		opt.Lenient[string]{},
+	} {
+		elem, ok = Unwrap(reflect.TypeOf(v))
+		a.True(ok, "%T", v)
+		a.Equal(reflect.TypeOf(""), elem, "%T", v)
+	}
+
+	_, ok = Unwrap(reflect.TypeOf([]int{}))
+	a.False(ok)
+
+	_, ok = Unwrap(reflect.TypeOf(opt.Blank[string]{}))
+	a.False(ok)
+}
+
+func TestGenerateWrappers(t *testing.T) {
+	a := assert.New(t)
+
+	type Listing struct {
+		Price   opt.StringEncoded[float64]                      `json:"price"`
+		Title   opt.BlankAsEmpty[string]                        `json:"title"`
+		Stock   opt.ZeroAsEmpty[int]                            `json:"stock"`
+		Tags    opt.EmptyWhen[[]string, opt.NoElements[string]] `json:"tags"`
+		Rating  opt.Lenient[float64]                            `json:"rating"`
+		Created time.Time                                       `json:"created"`
+	}
+
+	s := Generate[Listing]()
+	a.Equal([]string{"created"}, s.Required)
+	a.Equal(&Schema{Type: Types{"string", "null"}}, s.Properties["price"])
+	a.Equal(&Schema{Type: Types{"string", "null"}}, s.Properties["title"])
+	a.Equal(&Schema{Type: Types{"integer", "null"}}, s.Properties["stock"])
+	a.Equal(&Schema{Type: Types{"array", "null"}, Items: &Schema{Type: Types{"string"}}}, s.Properties["tags"])
+	a.Equal(&Schema{Type: Types{"number", "null"}}, s.Properties["rating"])
+}
+
+func TestNullable(t *testing.T) {
+	a := assert.New(t)
+
+	s := &Schema{Type: Types{"string"}}
+	n := Nullable(s)
+	a.Equal(Types{"string", "null"}, n.Type)
+	a.Equal(Types{"string"}, s.Type, "the original is not modified")
+	a.Equal(n, Nullable(n))
+
+	a.Equal(&Schema{}, Nullable(&Schema{}))
+
+	var types Types
+	a.NoError(json.Unmarshal([]byte(`"string"`), &types))
+	a.Equal(Types{"string"}, types)
+	a.NoError(json.Unmarshal([]byte(`["string","null"]`), &types))
+	a.Equal(Types{"string", "null"}, types)
+}
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+}
+
+func TestName(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal("User", Name(reflect.TypeOf(User{})))
+	a.Equal("Page_User", Name(reflect.TypeOf(Page[User]{})))
+	a.Equal("Page_Page_int", Name(reflect.TypeOf(Page[Page[int]]{})))
+}
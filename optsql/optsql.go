@@ -0,0 +1,105 @@
+// Package optsql builds SQL fragments from structs whose fields are optional
+// values, such as the patch structs decoded by PATCH endpoints.
+//
+// Columns are mapped using the `db:"column"` struct tag. Fields without a tag,
+// or with a tag of "-", are ignored. Embedded structs without a tag are walked
+// as if their fields were declared on the outer struct.
+package optsql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+// ErrNoFields is returned when none of the optional columns of a patch are
+// present. It signals that there is nothing to update so the query can be
+// skipped.
+var ErrNoFields = errors.New("optsql: no fields present in patch")
+
+// UpdateSet builds the SET clause of an UPDATE statement from `patch`, which
+// must be a struct or a pointer to one. Empty optional fields are left out of
+// the clause and non-optional fields are always included.
+//
+// Placeholders use the `$n` style and are numbered from `offset+1` so that the
+// clause can follow other parameters in the same statement:
+//
+//	set, args, err := optsql.UpdateSet(patch, 1)
+//	query := "UPDATE accounts " + set + " WHERE id = $1"
+//	db.Exec(query, append([]any{id}, args...)...)
+//
+// If none of the optional fields are present, ErrNoFields is returned even if
+// there are non-optional fields. Those, such as a version column, are written
+// along with a change but aren't a change on their own. A patch with no columns
+// at all also returns ErrNoFields.
+func UpdateSet(patch any, offset int) (clause string, args []any, err error) {
+	v := reflect.ValueOf(patch)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil, errors.New("optsql: patch is a nil pointer")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", nil, fmt.Errorf("optsql: patch must be a struct, got %s", v.Type())
+	}
+
+	var columns []string
+	optionals, present := collect(v, &columns, &args)
+
+	if len(columns) == 0 || (optionals > 0 && present == 0) {
+		return "", nil, ErrNoFields
+	}
+
+	var sb strings.Builder
+	sb.WriteString("SET ")
+	for i, c := range columns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(c)
+		sb.WriteString(" = $")
+		sb.WriteString(strconv.Itoa(offset + i + 1))
+	}
+
+	return sb.String(), args, nil
+}
+
+// collect appends the columns and arguments of `v` and counts its optional
+// fields and how many of them are present.
+func collect(v reflect.Value, columns *[]string, args *[]any) (optionals, present int) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("db")
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && !hasTag && f.Type.Kind() == reflect.Struct {
+			o, p := collect(v.Field(i), columns, args)
+			optionals, present = optionals+o, present+p
+			continue
+		}
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if optreflect.IsOptional(f.Type) {
+			optionals++
+			value, ok := optreflect.Get(fv)
+			if !ok {
+				continue
+			}
+			present++
+			fv = value
+		}
+
+		*columns = append(*columns, name)
+		*args = append(*args, fv.Interface())
+	}
+	return optionals, present
+}
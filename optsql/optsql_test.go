@@ -0,0 +1,89 @@
+package optsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Southclaws/opt"
+)
+
+type Timestamps struct {
+	UpdatedAt opt.Optional[string] `db:"updated_at"`
+}
+
+type AccountPatch struct {
+	Timestamps
+	Name     opt.Optional[string] `db:"name"`
+	Email    opt.Optional[string] `db:"email"`
+	Age      opt.Optional[int]    `db:"age"`
+	Version  int                  `db:"version"`
+	Ignored  opt.Optional[string] `db:"-"`
+	Untagged opt.Optional[string]
+}
+
+func TestUpdateSet(t *testing.T) {
+	a := assert.New(t)
+
+	set, args, err := UpdateSet(AccountPatch{
+		Name:     opt.New("Southclaws"),
+		Age:      opt.New(0),
+		Version:  2,
+		Ignored:  opt.New("ignored"),
+		Untagged: opt.New("untagged"),
+	}, 0)
+	a.NoError(err)
+	a.Equal("SET name = $1, age = $2, version = $3", set)
+	a.Equal([]any{"Southclaws", 0, 2}, args)
+}
+
+func TestUpdateSetOffset(t *testing.T) {
+	a := assert.New(t)
+
+	set, args, err := UpdateSet(&struct {
+		Timestamps
+		Email opt.Optional[string] `db:"email"`
+	}{
+		Timestamps: Timestamps{UpdatedAt: opt.New("now")},
+		Email:      opt.New("hello@example.com"),
+	}, 1)
+	a.NoError(err)
+	a.Equal("SET updated_at = $2, email = $3", set)
+	a.Equal([]any{"now", "hello@example.com"}, args)
+}
+
+func TestUpdateSetNoFields(t *testing.T) {
+	a := assert.New(t)
+
+	set, args, err := UpdateSet(struct {
+		Name opt.Optional[string] `db:"name"`
+	}{}, 0)
+	a.ErrorIs(err, ErrNoFields)
+	a.Empty(set)
+	a.Empty(args)
+
+	set, args, err = UpdateSet(AccountPatch{Version: 2}, 0)
+	a.ErrorIs(err, ErrNoFields, "non-optional fields alone aren't a change")
+	a.Empty(set)
+	a.Empty(args)
+
+	set, args, err = UpdateSet(struct {
+		Version int `db:"version"`
+	}{Version: 2}, 0)
+	a.NoError(err, "a patch without optional fields is always written")
+	a.Equal("SET version = $1", set)
+	a.Equal([]any{2}, args)
+
+	_, _, err = UpdateSet(struct{}{}, 0)
+	a.ErrorIs(err, ErrNoFields)
+}
+
+func TestUpdateSetInvalid(t *testing.T) {
+	a := assert.New(t)
+
+	_, _, err := UpdateSet("not a struct", 0)
+	a.Error(err)
+
+	_, _, err = UpdateSet((*AccountPatch)(nil), 0)
+	a.Error(err)
+}
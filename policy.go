@@ -0,0 +1,185 @@
+package opt
+
+import (
+	"strings"
+)
+
+// EmptyPolicy decides whether a decoded value should be treated as absent. It
+// mirrors the function passed to NewIf, but as a type so that it can be used
+// as a type parameter of EmptyWhen.
+type EmptyPolicy[T any] interface {
+	IsEmpty(value T) bool
+}
+
+// Blank is an EmptyPolicy which treats empty and whitespace-only strings as
+// empty.
+type Blank[T ~string] struct{}
+
+// IsEmpty implements EmptyPolicy.
+func (Blank[T]) IsEmpty(value T) bool { return strings.TrimSpace(string(value)) == "" }
+
+// Zero is an EmptyPolicy which treats the zero value of `T` as empty.
+type Zero[T comparable] struct{}
+
+// IsEmpty implements EmptyPolicy.
+func (Zero[T]) IsEmpty(value T) bool {
+	var zero T
+	return value == zero
+}
+
+// NoElements is an EmptyPolicy which treats slices with no elements as empty.
+type NoElements[E any] struct{}
+
+// IsEmpty implements EmptyPolicy.
+func (NoElements[E]) IsEmpty(value []E) bool { return len(value) == 0 }
+
+// EmptyWhen is an optional which is empty when the decoded value is considered
+// empty by the policy `P`. This keeps sentinel values sent by upstream APIs,
+// such as "N/A", out of domain types. It works with JSON, text and database/sql.
+//
+//	type NotApplicable struct{}
+//
+//	func (NotApplicable) IsEmpty(s string) bool { return s == "N/A" }
+//
+//	type Product struct {
+//		Colour opt.EmptyWhen[string, NotApplicable] `json:"colour"`
+//	}
+type EmptyWhen[T any, P EmptyPolicy[T]] Optional[T]
+
+// Optional converts this into a plain optional.
+func (e EmptyWhen[T, P]) Optional() Optional[T] { return Optional[T](e) }
+
+// Get returns the wrapped value if it's present, `ok` signals existence.
+func (e EmptyWhen[T, P]) Get() (value T, ok bool) { return e.Optional().Get() }
+
+// IsZero returns true if there's no value inside, see Optional.IsZero.
+func (e EmptyWhen[T, P]) IsZero() bool { return e == nil }
+
+// MarshalJSON marshals the wrapped value, see Optional.MarshalJSON.
+func (e EmptyWhen[T, P]) MarshalJSON() ([]byte, error) { return e.Optional().MarshalJSON() }
+
+// MarshalText marshals the wrapped value, see Optional.MarshalText.
+func (e EmptyWhen[T, P]) MarshalText() ([]byte, error) { return e.Optional().MarshalText() }
+
+// UnmarshalJSON unmarshals like Optional.UnmarshalJSON then applies the policy.
+func (e *EmptyWhen[T, P]) UnmarshalJSON(data []byte) error {
+	var p P
+	return decodeJSONIf((*Optional[T])(e), data, p.IsEmpty)
+}
+
+// UnmarshalText unmarshals like Optional.UnmarshalText then applies the policy.
+func (e *EmptyWhen[T, P]) UnmarshalText(text []byte) error {
+	var p P
+	return decodeTextIf((*Optional[T])(e), text, p.IsEmpty)
+}
+
+// Scan implements sql.Scanner, a NULL or a value the policy considers empty
+// produces an empty optional.
+func (e *EmptyWhen[T, P]) Scan(src any) error {
+	var p P
+	return scanIf((*Optional[T])(e), src, p.IsEmpty)
+}
+
+// BlankAsEmpty is an optional string which is empty when the decoded value is
+// empty or only whitespace. It works with JSON, text and database/sql.
+type BlankAsEmpty[T ~string] Optional[T]
+
+// Optional converts this into a plain optional.
+func (b BlankAsEmpty[T]) Optional() Optional[T] { return Optional[T](b) }
+
+// Get returns the wrapped value if it's present, `ok` signals existence.
+func (b BlankAsEmpty[T]) Get() (value T, ok bool) { return b.Optional().Get() }
+
+// IsZero returns true if there's no value inside, see Optional.IsZero.
+func (b BlankAsEmpty[T]) IsZero() bool { return b == nil }
+
+// MarshalJSON marshals the wrapped value, see Optional.MarshalJSON.
+func (b BlankAsEmpty[T]) MarshalJSON() ([]byte, error) { return b.Optional().MarshalJSON() }
+
+// MarshalText marshals the wrapped value, see Optional.MarshalText.
+func (b BlankAsEmpty[T]) MarshalText() ([]byte, error) { return b.Optional().MarshalText() }
+
+// UnmarshalJSON unmarshals like Optional.UnmarshalJSON then discards blanks.
+func (b *BlankAsEmpty[T]) UnmarshalJSON(data []byte) error {
+	return decodeJSONIf((*Optional[T])(b), data, Blank[T]{}.IsEmpty)
+}
+
+// UnmarshalText unmarshals like Optional.UnmarshalText then discards blanks.
+func (b *BlankAsEmpty[T]) UnmarshalText(text []byte) error {
+	return decodeTextIf((*Optional[T])(b), text, Blank[T]{}.IsEmpty)
+}
+
+// Scan implements sql.Scanner, a NULL or a blank string produces an empty
+// optional.
+func (b *BlankAsEmpty[T]) Scan(src any) error {
+	return scanIf((*Optional[T])(b), src, Blank[T]{}.IsEmpty)
+}
+
+// ZeroAsEmpty is an optional which is empty when the decoded value is the zero
+// value of `T`, such as "" or 0. It works with JSON, text and database/sql.
+type ZeroAsEmpty[T comparable] Optional[T]
+
+// Optional converts this into a plain optional.
+func (z ZeroAsEmpty[T]) Optional() Optional[T] { return Optional[T](z) }
+
+// Get returns the wrapped value if it's present, `ok` signals existence.
+func (z ZeroAsEmpty[T]) Get() (value T, ok bool) { return z.Optional().Get() }
+
+// IsZero returns true if there's no value inside, see Optional.IsZero.
+func (z ZeroAsEmpty[T]) IsZero() bool { return z == nil }
+
+// MarshalJSON marshals the wrapped value, see Optional.MarshalJSON.
+func (z ZeroAsEmpty[T]) MarshalJSON() ([]byte, error) { return z.Optional().MarshalJSON() }
+
+// MarshalText marshals the wrapped value, see Optional.MarshalText.
+func (z ZeroAsEmpty[T]) MarshalText() ([]byte, error) { return z.Optional().MarshalText() }
+
+// UnmarshalJSON unmarshals like Optional.UnmarshalJSON then discards zeros.
+func (z *ZeroAsEmpty[T]) UnmarshalJSON(data []byte) error {
+	return decodeJSONIf((*Optional[T])(z), data, Zero[T]{}.IsEmpty)
+}
+
+// UnmarshalText unmarshals like Optional.UnmarshalText then discards zeros.
+func (z *ZeroAsEmpty[T]) UnmarshalText(text []byte) error {
+	return decodeTextIf((*Optional[T])(z), text, Zero[T]{}.IsEmpty)
+}
+
+// Scan implements sql.Scanner, a NULL or a zero value produces an empty
+// optional.
+func (z *ZeroAsEmpty[T]) Scan(src any) error {
+	return scanIf((*Optional[T])(z), src, Zero[T]{}.IsEmpty)
+}
+
+func decodeJSONIf[T any](o *Optional[T], data []byte, empty func(T) bool) error {
+	var v Optional[T]
+	if err := v.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*o = discardIf(v, empty)
+	return nil
+}
+
+func decodeTextIf[T any](o *Optional[T], text []byte, empty func(T) bool) error {
+	var v Optional[T]
+	if err := v.UnmarshalText(text); err != nil {
+		return err
+	}
+	*o = discardIf(v, empty)
+	return nil
+}
+
+func scanIf[T any](o *Optional[T], src any, empty func(T) bool) error {
+	v, err := scan[T](src)
+	if err != nil {
+		return err
+	}
+	*o = discardIf(v, empty)
+	return nil
+}
+
+func discardIf[T any](o Optional[T], empty func(T) bool) Optional[T] {
+	if v, ok := o.Get(); ok && empty(v) {
+		return nil
+	}
+	return o
+}
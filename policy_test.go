@@ -0,0 +1,100 @@
+package opt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type notApplicable struct{}
+
+func (notApplicable) IsEmpty(s string) bool { return s == "N/A" }
+
+func TestEmptyPoliciesJSON(t *testing.T) {
+	a := assert.New(t)
+
+	type Data struct {
+		Name   BlankAsEmpty[string]                    `json:"name"`
+		Age    ZeroAsEmpty[int]                        `json:"age"`
+		Colour EmptyWhen[string, notApplicable]        `json:"colour"`
+		Tags   EmptyWhen[[]string, NoElements[string]] `json:"tags,omitempty"`
+	}
+
+	var d Data
+	err := json.Unmarshal([]byte(`{"name":"  ","age":0,"colour":"N/A","tags":[]}`), &d)
+	a.NoError(err)
+	a.Empty(d.Name)
+	a.Empty(d.Age)
+	a.Empty(d.Colour)
+	a.Empty(d.Tags)
+
+	b, err := json.Marshal(d)
+	a.NoError(err)
+	a.Equal(`{"name":null,"age":null,"colour":null}`, string(b))
+
+	err = json.Unmarshal([]byte(`{"name":" Southclaws ","age":69,"colour":"red","tags":["a"]}`), &d)
+	a.NoError(err)
+	a.Equal(New(" Southclaws "), d.Name.Optional())
+	a.Equal(New(69), d.Age.Optional())
+	a.Equal(New("red"), d.Colour.Optional())
+	a.Equal(New([]string{"a"}), d.Tags.Optional())
+
+	v, ok := d.Age.Get()
+	a.True(ok)
+	a.Equal(69, v)
+
+	b, err = json.Marshal(d)
+	a.NoError(err)
+	a.Equal(`{"name":" Southclaws ","age":69,"colour":"red","tags":["a"]}`, string(b))
+
+	a.Error(json.Unmarshal([]byte(`{"age":"old"}`), &d))
+}
+
+func TestEmptyPoliciesText(t *testing.T) {
+	a := assert.New(t)
+
+	var b BlankAsEmpty[string]
+	a.NoError(b.UnmarshalText([]byte(" ")))
+	a.Empty(b)
+	a.NoError(b.UnmarshalText([]byte("value")))
+	a.Equal(New("value"), b.Optional())
+
+	var z ZeroAsEmpty[int]
+	a.NoError(z.UnmarshalText([]byte("0")))
+	a.Empty(z)
+	a.NoError(z.UnmarshalText([]byte("5")))
+	a.Equal(New(5), z.Optional())
+	a.Error(z.UnmarshalText([]byte("five")))
+
+	var e EmptyWhen[string, notApplicable]
+	a.NoError(e.UnmarshalText([]byte("N/A")))
+	a.Empty(e)
+
+	text, err := z.MarshalText()
+	a.NoError(err)
+	a.Equal("5", string(text))
+}
+
+func TestEmptyPoliciesScan(t *testing.T) {
+	a := assert.New(t)
+
+	var b BlankAsEmpty[string]
+	a.NoError(b.Scan([]byte("")))
+	a.Empty(b)
+	a.NoError(b.Scan("value"))
+	a.Equal(New("value"), b.Optional())
+	a.NoError(b.Scan(nil))
+	a.Empty(b)
+
+	var z ZeroAsEmpty[int32]
+	a.NoError(z.Scan(int64(0)))
+	a.Empty(z)
+	a.NoError(z.Scan(int64(5)))
+	a.Equal(New[int32](5), z.Optional())
+
+	var e EmptyWhen[string, notApplicable]
+	a.NoError(e.Scan("N/A"))
+	a.Empty(e)
+	a.True(e.IsZero())
+}
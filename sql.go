@@ -0,0 +1,125 @@
+package opt
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+// scan converts a value from a database/sql driver into an optional. A NULL
+// produces an empty optional. If `*T` implements sql.Scanner then that is used,
+// otherwise the driver value is converted to `T`, with []byte and string values
+// parsed using the same rules as UnmarshalText. Numbers which don't fit in `T`
+// exactly produce an error rather than being truncated.
+func scan[T any](src any) (Optional[T], error) {
+	if src == nil {
+		return nil, nil
+	}
+
+	var v T
+	if s, ok := any(&v).(sql.Scanner); ok {
+		if err := s.Scan(src); err != nil {
+			return nil, err
+		}
+		return New(v), nil
+	}
+
+	if t, ok := src.(T); ok {
+		return New(t), nil
+	}
+
+	dst := reflect.ValueOf(&v).Elem()
+	switch s := src.(type) {
+	case []byte:
+		if err := optreflect.ParseText(dst, string(s)); err != nil {
+			return nil, err
+		}
+		return New(v), nil
+
+	case string:
+		if err := optreflect.ParseText(dst, s); err != nil {
+			return nil, err
+		}
+		return New(v), nil
+
+	case int64, float64, bool, time.Time:
+		sv := reflect.ValueOf(src)
+		if sameKindClass(sv.Kind(), dst.Kind()) && sv.Type().ConvertibleTo(dst.Type()) {
+			if err := checkConvert(sv, dst.Type()); err != nil {
+				return nil, err
+			}
+			dst.Set(sv.Convert(dst.Type()))
+			return New(v), nil
+		}
+	}
+
+	return nil, fmt.Errorf("opt: cannot scan %T into %T", src, v)
+}
+
+// checkConvert reports an error if converting `src` to `t` would lose
+// information, in the same way as database/sql does for its own destinations:
+// values which overflow `t`, negative values for unsigned types and floats with
+// a fractional part for integer types are all rejected.
+func checkConvert(src reflect.Value, t reflect.Type) error {
+	dst := reflect.New(t).Elem()
+
+	var overflow bool
+	switch src.Kind() {
+	case reflect.Int64:
+		i := src.Int()
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			overflow = dst.OverflowInt(i)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			overflow = i < 0 || dst.OverflowUint(uint64(i))
+		}
+
+	case reflect.Float64:
+		f := src.Float()
+		switch t.Kind() {
+		case reflect.Float32, reflect.Float64:
+			overflow = dst.OverflowFloat(f)
+		default:
+			if f != math.Trunc(f) {
+				return fmt.Errorf("opt: cannot scan %v into %s without losing its fractional part", f, t)
+			}
+			bits := t.Bits()
+			switch t.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				limit := math.Ldexp(1, bits-1)
+				overflow = f < -limit || f >= limit
+			default:
+				overflow = f < 0 || f >= math.Ldexp(1, bits)
+			}
+		}
+	}
+
+	if overflow {
+		return fmt.Errorf("opt: cannot scan %v into %s, value out of range", src.Interface(), t)
+	}
+	return nil
+}
+
+// sameKindClass prevents conversions which reflect allows but which would be
+// surprising for database values, such as an int64 becoming a string rune.
+func sameKindClass(a, b reflect.Kind) bool {
+	return kindClass(a) != 0 && kindClass(a) == kindClass(b)
+}
+
+func kindClass(k reflect.Kind) int {
+	switch k {
+	case reflect.Bool:
+		return 1
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return 2
+	case reflect.Struct:
+		return 3
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package opt
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanDriverValue(t *testing.T) {
+	a := assert.New(t)
+
+	s, err := scan[string]([]byte("value"))
+	a.NoError(err)
+	a.Equal(New("value"), s)
+
+	i, err := scan[int](int64(5))
+	a.NoError(err)
+	a.Equal(New(5), i)
+
+	i, err = scan[int]("5")
+	a.NoError(err)
+	a.Equal(New(5), i)
+
+	i, err = scan[int](nil)
+	a.NoError(err)
+	a.Empty(i)
+
+	f, err := scan[float32](float64(1.5))
+	a.NoError(err)
+	a.Equal(New[float32](1.5), f)
+
+	now := time.Now()
+	tm, err := scan[time.Time](now)
+	a.NoError(err)
+	a.Equal(New(now), tm)
+
+	ns, err := scan[sql.NullString]("value")
+	a.NoError(err)
+	a.Equal(New(sql.NullString{String: "value", Valid: true}), ns)
+
+	_, err = scan[string](int64(65))
+	a.Error(err, "an int64 must not become a rune")
+
+	_, err = scan[int]("five")
+	a.Error(err)
+}
+
+func TestScanDriverValueLoss(t *testing.T) {
+	a := assert.New(t)
+
+	_, err := scan[int8](int64(300))
+	a.ErrorContains(err, "out of range")
+
+	_, err = scan[uint8](int64(-1))
+	a.ErrorContains(err, "out of range")
+
+	_, err = scan[int](1.9)
+	a.ErrorContains(err, "fractional")
+
+	_, err = scan[int8](float64(200))
+	a.ErrorContains(err, "out of range")
+
+	_, err = scan[uint](float64(-1))
+	a.ErrorContains(err, "out of range")
+
+	_, err = scan[float32](math.MaxFloat64)
+	a.ErrorContains(err, "out of range")
+
+	var z ZeroAsEmpty[int8]
+	a.Error(z.Scan(int64(300)))
+	a.Empty(z)
+
+	i8, err := scan[int8](int64(-128))
+	a.NoError(err)
+	a.Equal(New[int8](-128), i8)
+
+	u8, err := scan[uint8](float64(255))
+	a.NoError(err)
+	a.Equal(New[uint8](255), u8)
+
+	i, err := scan[int](float64(2))
+	a.NoError(err)
+	a.Equal(New(2), i)
+}
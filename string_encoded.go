@@ -0,0 +1,99 @@
+package opt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StringEncodable is the set of types which StringEncoded can wrap.
+type StringEncodable interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~bool
+}
+
+// StringEncoded is an optional number or bool which is encoded in JSON as a
+// string, such as `"1234"`, in the same way as the `json:",string"` option
+// does for plain fields. This is useful for int64 IDs which would otherwise
+// lose precision in JavaScript. An empty optional is still `null` and is left
+// out by `omitempty`.
+//
+// The `,string` option has no effect on types with their own marshalers when
+// using the classic encoding/json implementation, hence this wrapper. When
+// encoding/json is backed by encoding/json/v2, `,string` works on a plain
+// Optional too.
+type StringEncoded[T StringEncodable] Optional[T]
+
+// NewStringEncoded wraps the input value in a string encoded optional.
+func NewStringEncoded[T StringEncodable](value T) StringEncoded[T] {
+	return StringEncoded[T]{value}
+}
+
+// Optional converts this into a plain optional.
+func (s StringEncoded[T]) Optional() Optional[T] {
+	return Optional[T](s)
+}
+
+// Get returns the wrapped value if it's present, `ok` signals existence.
+func (s StringEncoded[T]) Get() (value T, ok bool) {
+	return s.Optional().Get()
+}
+
+// IsZero returns true if there's no value inside, see Optional.IsZero.
+func (s StringEncoded[T]) IsZero() bool {
+	return s == nil
+}
+
+// MarshalJSON marshals the wrapped value to a JSON string. If there is no value
+// being wrapped, `null` is marshaled.
+func (s StringEncoded[T]) MarshalJSON() (data []byte, err error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+
+	b, err := json.Marshal(s[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(strconv.Quote(string(b))), nil
+}
+
+// UnmarshalJSON unmarshals a JSON string containing a number or bool into a
+// value wrapped by this optional. A `null` produces an empty optional.
+func (s *StringEncoded[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*s = nil
+		return nil
+	}
+
+	var str string
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		return fmt.Errorf("opt: invalid use of string encoded optional, expected a JSON string: %w", err)
+	}
+
+	v, err := parseStringEncoded[T](str)
+	if err != nil {
+		return err
+	}
+
+	*s = StringEncoded[T]{v}
+	return nil
+}
+
+// parseStringEncoded parses the contents of the JSON string `str` as a `T`. A
+// quoted "null" is rejected, since json.Unmarshal would otherwise ignore it and
+// leave a present zero value.
+func parseStringEncoded[T StringEncodable](str string) (T, error) {
+	var v T
+	if strings.TrimSpace(str) == "null" {
+		return v, fmt.Errorf("opt: invalid use of string encoded optional, cannot unmarshal %q into %T, use a JSON null for an empty value", str, v)
+	}
+	if err := json.Unmarshal([]byte(str), &v); err != nil {
+		return v, fmt.Errorf("opt: invalid use of string encoded optional, cannot unmarshal %q into %T: %w", str, v, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,52 @@
+package opt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringEncoded(t *testing.T) {
+	a := assert.New(t)
+
+	type Data struct {
+		ID     StringEncoded[int64]   `json:"id"`
+		Parent StringEncoded[int64]   `json:"parent"`
+		Score  StringEncoded[float64] `json:"score,omitempty"`
+		Active StringEncoded[bool]    `json:"active"`
+	}
+
+	in := Data{
+		ID:     NewStringEncoded[int64](9007199254740993),
+		Active: NewStringEncoded(true),
+	}
+
+	b, err := json.Marshal(in)
+	a.NoError(err)
+	a.Equal(`{"id":"9007199254740993","parent":null,"active":"true"}`, string(b))
+
+	var out Data
+	err = json.Unmarshal(b, &out)
+	a.NoError(err)
+	a.Equal(in, out)
+
+	id, ok := out.ID.Get()
+	a.True(ok)
+	a.Equal(int64(9007199254740993), id)
+	_, ok = out.Parent.Get()
+	a.False(ok)
+	a.Equal(New[int64](9007199254740993), out.ID.Optional())
+
+	err = json.Unmarshal([]byte(`{"score":"1.5"}`), &out)
+	a.NoError(err)
+	a.Equal(1.5, out.Score.Optional().OrZero())
+
+	a.Error(json.Unmarshal([]byte(`{"id":5}`), &out))
+	a.Error(json.Unmarshal([]byte(`{"id":"five"}`), &out))
+	a.Error(json.Unmarshal([]byte(`{"active":"yes"}`), &out))
+
+	out = Data{}
+	a.ErrorContains(json.Unmarshal([]byte(`{"id":"null"}`), &out), "use a JSON null")
+	a.Empty(out.ID, "a quoted null is not a present zero")
+}
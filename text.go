@@ -0,0 +1,55 @@
+package opt
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Southclaws/opt/internal/optreflect"
+)
+
+// MarshalText marshals the wrapped value to text. If `T` or `*T` implements
+// encoding.TextMarshaler then that is used, otherwise basic kinds such as
+// strings, numbers and bools are formatted with strconv. An empty optional
+// produces empty text.
+func (o Optional[T]) MarshalText() (text []byte, err error) {
+	if o == nil {
+		return []byte{}, nil
+	}
+
+	s, err := optreflect.FormatText(reflect.ValueOf(&o[0]).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(s), nil
+}
+
+// UnmarshalText unmarshals text into a value wrapped by this optional. If `T`
+// implements encoding.TextUnmarshaler then that is used, otherwise basic kinds
+// are parsed with strconv. Empty text produces an empty optional.
+func (o *Optional[T]) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*o = NewEmpty[T]()
+		return nil
+	}
+
+	var v T
+	err := optreflect.ParseText(reflect.ValueOf(&v).Elem(), string(text))
+	if err != nil {
+		return err
+	}
+
+	*o = New(v)
+	return nil
+}
+
+// Scan implements fmt.Scanner so optionals can be filled by `fmt.Sscan` and
+// friends. The next space-delimited token is parsed with UnmarshalText.
+func (o *Optional[T]) Scan(state fmt.ScanState, verb rune) error {
+	token, err := state.Token(true, nil)
+	if err != nil {
+		return err
+	}
+
+	return o.UnmarshalText(token)
+}
@@ -0,0 +1,82 @@
+package opt
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalText(t *testing.T) {
+	a := assert.New(t)
+
+	tests := []struct {
+		Marshal  func() ([]byte, error)
+		Expected string
+	}{
+		{NewEmpty[string]().MarshalText, ""},
+		{New("value").MarshalText, "value"},
+		{New(69).MarshalText, "69"},
+		{New(-1.5).MarshalText, "-1.5"},
+		{New(true).MarshalText, "true"},
+		{New(time.Duration(5)).MarshalText, "5"},
+		{New(net.IPv4(127, 0, 0, 1)).MarshalText, "127.0.0.1"},
+		{New(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)).MarshalText, "2006-01-02T15:04:05Z"},
+	}
+
+	for _, test := range tests {
+		b, err := test.Marshal()
+		a.NoError(err)
+		a.Equal(test.Expected, string(b))
+	}
+
+	_, err := New(struct{}{}).MarshalText()
+	a.Error(err)
+}
+
+func TestUnmarshalText(t *testing.T) {
+	a := assert.New(t)
+
+	var s Optional[string]
+	a.NoError(s.UnmarshalText([]byte("value")))
+	a.Equal(New("value"), s)
+	a.NoError(s.UnmarshalText([]byte("")))
+	a.Equal(NewEmpty[string](), s)
+
+	var i Optional[int]
+	a.NoError(i.UnmarshalText([]byte("69")))
+	a.Equal(New(69), i)
+	a.Error(i.UnmarshalText([]byte("value")))
+	a.Equal(New(69), i, "failed unmarshal leaves the optional untouched")
+
+	var b Optional[bool]
+	a.NoError(b.UnmarshalText([]byte("false")))
+	a.Equal(New(false), b)
+
+	var ip Optional[net.IP]
+	a.NoError(ip.UnmarshalText([]byte("127.0.0.1")))
+	a.Equal("127.0.0.1", ip.String())
+
+	var tm Optional[time.Time]
+	a.NoError(tm.UnmarshalText([]byte("2006-01-02T15:04:05Z")))
+	a.Equal(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), tm.OrZero())
+}
+
+func TestScan(t *testing.T) {
+	a := assert.New(t)
+
+	var (
+		name Optional[string]
+		age  Optional[int]
+	)
+	n, err := fmt.Sscan("Southclaws 69", &name, &age)
+	a.NoError(err)
+	a.Equal(2, n)
+	a.Equal(New("Southclaws"), name)
+	a.Equal(New(69), age)
+
+	_, err = fmt.Sscan("old", &age)
+	a.Error(err)
+}
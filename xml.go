@@ -0,0 +1,66 @@
+package opt
+
+import (
+	"encoding/xml"
+)
+
+// MarshalXML encodes the wrapped value as an element using `start`. If there
+// is no value being wrapped, nothing is written and the element is omitted.
+func (o Optional[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if o == nil {
+		return nil
+	}
+	return e.EncodeElement(&o[0], start)
+}
+
+// UnmarshalXML decodes the element into a value wrapped by this optional. An
+// element which is not present leaves the optional untouched, so it's empty.
+func (o *Optional[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v T
+	err := d.DecodeElement(&v, &start)
+	if err != nil {
+		return err
+	}
+
+	*o = New(v)
+	return nil
+}
+
+// MarshalXMLAttr encodes the wrapped value as an attribute. If `T` or `*T`
+// implements xml.MarshalerAttr then that is used, otherwise the value is
+// encoded using MarshalText. If there is no value being wrapped, the attribute
+// is omitted.
+func (o Optional[T]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if o == nil {
+		return xml.Attr{}, nil
+	}
+
+	if m, ok := any(&o[0]).(xml.MarshalerAttr); ok {
+		return m.MarshalXMLAttr(name)
+	}
+
+	text, err := o.MarshalText()
+	if err != nil {
+		return xml.Attr{}, err
+	}
+
+	return xml.Attr{Name: name, Value: string(text)}, nil
+}
+
+// UnmarshalXMLAttr decodes the attribute into a value wrapped by this optional.
+// If `T` implements xml.UnmarshalerAttr then that is used, otherwise the value
+// is decoded using UnmarshalText so an empty attribute is an empty optional.
+func (o *Optional[T]) UnmarshalXMLAttr(attr xml.Attr) error {
+	var v T
+	if u, ok := any(&v).(xml.UnmarshalerAttr); ok {
+		err := u.UnmarshalXMLAttr(attr)
+		if err != nil {
+			return err
+		}
+
+		*o = New(v)
+		return nil
+	}
+
+	return o.UnmarshalText([]byte(attr.Value))
+}
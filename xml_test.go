@@ -0,0 +1,57 @@
+package opt
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXML(t *testing.T) {
+	a := assert.New(t)
+
+	type Address struct {
+		City string `xml:"city"`
+	}
+	type Data struct {
+		XMLName xml.Name          `xml:"data"`
+		ID      string            `xml:"id,attr"`
+		Nick    Optional[string]  `xml:"nick,attr"`
+		Name    Optional[string]  `xml:"name"`
+		Age     Optional[int]     `xml:"age"`
+		Address Optional[Address] `xml:"address"`
+	}
+
+	in := Data{ID: "southclaws"}
+
+	b1, err := xml.Marshal(in)
+	a.NoError(err)
+	a.Equal(`<data id="southclaws"></data>`, string(b1))
+
+	in.Nick = New("SC")
+	in.Age = New(0)
+	in.Address = New(Address{City: "London"})
+
+	b2, err := xml.Marshal(in)
+	a.NoError(err)
+	a.Equal(`<data id="southclaws" nick="SC"><age>0</age><address><city>London</city></address></data>`, string(b2))
+
+	var out Data
+	err = xml.Unmarshal(b2, &out)
+	a.NoError(err)
+	a.Equal("southclaws", out.ID)
+	a.Equal(New("SC"), out.Nick)
+	a.Empty(out.Name)
+	a.Equal(New(0), out.Age)
+	a.Equal(New(Address{City: "London"}), out.Address)
+
+	out = Data{}
+	err = xml.Unmarshal(b1, &out)
+	a.NoError(err)
+	a.Empty(out.Nick)
+	a.Empty(out.Age)
+	a.Empty(out.Address)
+
+	err = xml.Unmarshal([]byte(`<data><age>old</age></data>`), &out)
+	a.Error(err)
+}
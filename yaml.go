@@ -0,0 +1,39 @@
+//go:build yaml
+
+package opt
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML marshals the value being wrapped to YAML. If there is no value
+// being wrapped, it's marshaled as `null`. Unlike the other codecs, the value is
+// passed by value since yaml.v3 never uses pointer receiver marshalers on plain
+// fields either.
+//
+// YAML support is only compiled in with the `yaml` build tag so that the core
+// package doesn't depend on gopkg.in/yaml.v3 unless it's needed.
+func (o Optional[T]) MarshalYAML() (any, error) {
+	if o == nil {
+		return nil, nil
+	}
+	return o[0], nil
+}
+
+// UnmarshalYAML unmarshals the YAML node into a value wrapped by this optional.
+// A `null` or `~` node, as well as a missing key, produces an empty optional.
+func (o *Optional[T]) UnmarshalYAML(node *yaml.Node) error {
+	if node.ShortTag() == "!!null" {
+		*o = NewEmpty[T]()
+		return nil
+	}
+
+	var v T
+	err := node.Decode(&v)
+	if err != nil {
+		return err
+	}
+
+	*o = New(v)
+	return nil
+}
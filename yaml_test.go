@@ -0,0 +1,92 @@
+//go:build yaml
+
+package opt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestYAML(t *testing.T) {
+	a := assert.New(t)
+
+	type Database struct {
+		Host string `yaml:"host"`
+		Port int    `yaml:"port"`
+	}
+	type Config struct {
+		Name     Optional[string]   `yaml:"name"`
+		Tags     Optional[[]string] `yaml:"tags,omitempty"`
+		Port     Optional[int]      `yaml:"port"`
+		Database Optional[Database] `yaml:"database"`
+	}
+
+	in := Config{}
+
+	b1, err := yaml.Marshal(in)
+	a.NoError(err)
+	a.Equal("name: null\nport: null\ndatabase: null\n", string(b1))
+
+	in.Name = New("opt")
+	in.Tags = New([]string{"a", "b"})
+	in.Port = New(0)
+	in.Database = New(Database{Host: "localhost", Port: 5432})
+
+	b2, err := yaml.Marshal(in)
+	a.NoError(err)
+	a.Equal(`name: opt
+tags:
+    - a
+    - b
+port: 0
+database:
+    host: localhost
+    port: 5432
+`, string(b2))
+
+	var out Config
+	err = yaml.Unmarshal(b2, &out)
+	a.NoError(err)
+	a.Equal(in, out)
+
+	out = Config{}
+	err = yaml.Unmarshal([]byte("name: ~\nport: null\n"), &out)
+	a.NoError(err)
+	a.Empty(out.Name)
+	a.Empty(out.Tags)
+	a.Empty(out.Port)
+	a.Empty(out.Database)
+
+	err = yaml.Unmarshal([]byte("port: old\n"), &out)
+	a.Error(err)
+}
+
+func TestUnmarshalYAMLNull(t *testing.T) {
+	a := assert.New(t)
+
+	o := New(1)
+	a.NoError(o.UnmarshalYAML(&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "~"}))
+	a.Empty(o)
+}
+
+type yamlPointerMarshaler struct{ V string }
+
+func (m *yamlPointerMarshaler) MarshalYAML() (any, error) { return "yaml:" + m.V, nil }
+
+func TestYAMLPointerMarshaler(t *testing.T) {
+	a := assert.New(t)
+
+	plain, err := yaml.Marshal(&struct {
+		V yamlPointerMarshaler `yaml:"v"`
+	}{yamlPointerMarshaler{"a"}})
+	a.NoError(err)
+
+	optional, err := yaml.Marshal(&struct {
+		V Optional[yamlPointerMarshaler] `yaml:"v"`
+	}{New(yamlPointerMarshaler{"a"})})
+	a.NoError(err)
+
+	a.Equal(string(plain), string(optional))
+}
@@ -0,0 +1,40 @@
+//go:build !yaml
+
+package opt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// TestYAMLWithoutTag pins down what yaml.v3 does without the `yaml` build tag,
+// where it falls back to MarshalText and UnmarshalText, since that's what the
+// README warns about.
+func TestYAMLWithoutTag(t *testing.T) {
+	a := assert.New(t)
+
+	type Database struct {
+		Host string `yaml:"host"`
+	}
+	type Config struct {
+		Port     Optional[int]      `yaml:"port"`
+		Name     Optional[string]   `yaml:"name"`
+		Database Optional[Database] `yaml:"database"`
+	}
+
+	b, err := yaml.Marshal(Config{Port: New(1)})
+	a.NoError(err)
+	a.Equal("port: \"1\"\nname: \"\"\ndatabase: \"\"\n", string(b), "values are quoted text and empty optionals are blank strings")
+
+	_, err = yaml.Marshal(Config{Database: New(Database{Host: "localhost"})})
+	a.ErrorContains(err, "cannot format")
+
+	var c Config
+	a.NoError(yaml.Unmarshal([]byte("port: 1\nname: null\n"), &c))
+	a.Equal(New(1), c.Port, "scalars still decode through UnmarshalText")
+	a.Empty(c.Name)
+
+	a.Error(yaml.Unmarshal([]byte("database:\n  host: localhost\n"), &c))
+}